@@ -4,17 +4,20 @@ package buildsqlx
 func (r *DB) Exists() (query string, values []interface{}) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+		builder.setErr(ErrNoTable)
+		return
 	}
 
+	r.applyScopes()
+
 	builder.WriteString("SELECT EXISTS").
 		Pad().
 		Nested(func(s *sqlBuilder) {
 			s.WriteString("SELECT 1 FROM")
 			s.Pad()
 			s.Ident(builder.table)
-			s.Pad().WriteString(buildClauses(builder))
-		}).Pad()
+			s.WriteString(buildClauses(builder))
+		})
 
 	query = builder.String()
 	values = append(values, builder.where.args...)
@@ -26,27 +29,32 @@ func (r *DB) Exists() (query string, values []interface{}) {
 func (r *DB) Query() (query string, values []interface{}) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+		builder.setErr(ErrNoTable)
+		return
 	}
 
-	if len(builder.union) > 0 { // got union - need different logic to glue
-		for _, uBuilder := range builder.union {
-			builder.WriteString(uBuilder)
-			builder.Pad().WriteString("UNION").Pad()
-
-			if builder.isUnionAll {
-				builder.WriteString("ALL").Pad()
-			}
-		}
-
-		query += builder.buildSelect()
-	} else { // std builder
-		query = builder.buildSelect()
-	}
+	r.applyScopes()
 
+	query = builder.buildQuery()
+	values = append(values, r.Builder.unionArgs...)
 	values = append(values, r.Builder.where.args...)
 	values = append(values, r.Builder.having.args...)
 
 	return
+}
+
+// buildQuery constructs a select statement glued with unions if there are any
+func (r *builder) buildQuery() string {
+	if len(r.union) > 0 { // got union - need different logic to glue
+		for _, uBuilder := range r.union {
+			r.WriteString(uBuilder)
+			r.Pad().WriteString("UNION").Pad()
+
+			if r.isUnionAll {
+				r.WriteString("ALL").Pad()
+			}
+		}
+	}
 
+	return r.buildSelect()
 }
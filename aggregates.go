@@ -1,19 +1,65 @@
 package buildsqlx
 
-// Count counts
+import "strings"
+
+// Count counts rows matched by the query, grouped, distinct, limited or unioned queries
+// are wrapped as SELECT COUNT(*) FROM (...) AS sub to count the rows they return
 func (r *DB) Count() (query string, args []interface{}) {
+	r.applyScopes()
 	builder := r.Builder
-	builder.columns = []string{"COUNT(*)"}
-	query = builder.buildSelect()
+	if builder.isCountWrapped() {
+		sub := builder.fork()
+		// ordering doesn't change the amount of rows unless it picks the LIMIT window
+		if sub.limit == 0 {
+			sub.orderBy = nil
+			sub.orderByRaw = nil
+		}
+
+		builder.WriteString("SELECT COUNT(*) FROM").Pad().
+			Nested(func(s *sqlBuilder) {
+				s.WriteString(sub.buildQuery())
+			}).
+			Pad().WriteString("AS sub")
+		query = builder.String()
+	} else {
+		builder.columns = []Expr{Raw("COUNT(*)")}
+		builder.orderBy = nil
+		builder.orderByRaw = nil
+		query = builder.buildSelect()
+	}
+
+	args = append(args, builder.unionArgs...)
 	args = append(args, builder.where.args...)
 	args = append(args, builder.having.args...)
 	return
 }
 
+// isCountWrapped reports whether COUNT(*) can't replace the select list in place
+func (r *builder) isCountWrapped() bool {
+	if len(r.groupBy) > 0 || r.distinct || r.limit > 0 || len(r.union) > 0 {
+		return true
+	}
+
+	for _, col := range r.columns {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(col.sql)), "DISTINCT ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// aggregate returns fn(column) expression with the column quoted
+func (r *builder) aggregate(fn, column string) Expr {
+	r.allow(column)
+	return Raw(fn + "(" + r.Quote(column) + ")")
+}
+
 // Avg calculates average for specified column
 func (r *DB) Avg(column string) (query string, args []interface{}) {
+	r.applyScopes()
 	builder := r.Builder
-	builder.columns = []string{"AVG(" + column + ")"}
+	builder.columns = []Expr{builder.aggregate("AVG", column)}
 	query = builder.buildSelect()
 	args = append(args, builder.where.args...)
 	args = append(args, builder.having.args...)
@@ -22,8 +68,9 @@ func (r *DB) Avg(column string) (query string, args []interface{}) {
 
 // Min calculates minimum for specified column
 func (r *DB) Min(column string) (query string, args []interface{}) {
+	r.applyScopes()
 	builder := r.Builder
-	builder.columns = []string{"MIN(" + column + ")"}
+	builder.columns = []Expr{builder.aggregate("MIN", column)}
 	query = builder.buildSelect()
 	args = append(args, builder.where.args...)
 	args = append(args, builder.having.args...)
@@ -32,8 +79,9 @@ func (r *DB) Min(column string) (query string, args []interface{}) {
 
 // Max calculates maximum for specified column
 func (r *DB) Max(column string) (query string, args []interface{}) {
+	r.applyScopes()
 	builder := r.Builder
-	builder.columns = []string{"MAX(" + column + ")"}
+	builder.columns = []Expr{builder.aggregate("MAX", column)}
 	query = builder.buildSelect()
 	args = append(args, builder.where.args...)
 	args = append(args, builder.having.args...)
@@ -42,8 +90,9 @@ func (r *DB) Max(column string) (query string, args []interface{}) {
 
 // Sum calculates sum for specified column
 func (r *DB) Sum(column string) (query string, args []interface{}) {
+	r.applyScopes()
 	builder := r.Builder
-	builder.columns = []string{"SUM(" + column + ")"}
+	builder.columns = []Expr{builder.aggregate("SUM", column)}
 	query = builder.buildSelect()
 	args = append(args, builder.where.args...)
 	args = append(args, builder.having.args...)
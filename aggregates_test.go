@@ -0,0 +1,67 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Count(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(d *DB) *DB
+		wantSql  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "plain",
+			build: func(d *DB) *DB {
+				return d.Table("users").Where("points", OpGT, 10).OrderBy("points", "DESC")
+			},
+			wantSql:  "SELECT COUNT(*) FROM `users` WHERE `users`.`points` > ?",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name: "group by",
+			build: func(d *DB) *DB {
+				return d.Table("users").Select("account_id").GroupBy("account_id").Having("account_id", OpGT, 100).OrderBy("account_id", "ASC")
+			},
+			wantSql:  "SELECT COUNT(*) FROM (SELECT `account_id` FROM `users` GROUP BY `account_id` HAVING `users`.`account_id` > ?) AS sub",
+			wantArgs: []interface{}{100},
+		},
+		{
+			name: "distinct",
+			build: func(d *DB) *DB {
+				return d.Table("users").Select("email").Distinct()
+			},
+			wantSql: "SELECT COUNT(*) FROM (SELECT DISTINCT `email` FROM `users`) AS sub",
+		},
+		{
+			name: "limit",
+			build: func(d *DB) *DB {
+				return d.Table("users").OrderBy("id", "ASC").Limit(10)
+			},
+			wantSql: "SELECT COUNT(*) FROM (SELECT * FROM `users` ORDER BY `users`.`id` ASC LIMIT 10) AS sub",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := tt.build(newDB(conn)).Count()
+			assert.Equal(t, tt.wantSql, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestDB_CountUnion(t *testing.T) {
+	d := newDB(conn)
+	query, args := d.Table("posts").Select("title").Where("status", OpEQ, "published").Union().
+		Table("users").Select("name").Where("active", OpEQ, 1).Count()
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT `title` FROM `posts` WHERE `posts`.`status` = ? UNION SELECT `name` FROM `users` WHERE `users`.`active` = ?) AS sub", query)
+	assert.Equal(t, []interface{}{"published", 1}, args)
+
+	query, args = newDB(conn).Table("posts").Select("title").Where("status", OpEQ, "published").Union().
+		Table("users").Select("name").Where("active", OpEQ, 1).Query()
+	assert.Equal(t, "SELECT `title` FROM `posts` WHERE `posts`.`status` = ? UNION SELECT `name` FROM `users` WHERE `users`.`active` = ?", query)
+	assert.Equal(t, []interface{}{"published", 1}, args)
+}
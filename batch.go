@@ -0,0 +1,379 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrNoBatchKeys              = errors.New("sql: batch update needs at least one key column")
+	ErrBatchKeyMissing          = errors.New("sql: batch row misses key column")
+	ErrBatchColumnsMismatch     = errors.New("sql: batch rows have different columns")
+	ErrBatchLengthMismatch      = errors.New("sql: batch columns have different amount of values")
+	ErrNoBatchColumns           = errors.New("sql: batch rows have nothing to update")
+	ErrInvalidBatchRows         = errors.New("sql: batch rows must be a slice of structs, maps or Pairs")
+	ErrBatchStrategyUnsupported = errors.New("sql: batch update strategy is not supported by the dialect")
+	ErrBatchTypeMissing         = errors.New("sql: batch column has no type")
+	ErrInvalidBatchType         = errors.New("sql: invalid batch column type")
+)
+
+// batchType matches the type names BatchTypes accepts, ex.: integer, varchar(64), numeric(10, 2), timestamp with time zone, uuid[]
+var batchType = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_ ]*(\(\d+(, ?\d+)?\))?(\[\])?$`)
+
+// BatchStrategy picks the statement UpdateRows is rendered with
+type BatchStrategy int
+
+const (
+	// BatchCase renders UPDATE t SET col = CASE WHEN key = ? THEN ? ... ELSE col END WHERE key IN (...)
+	BatchCase BatchStrategy = iota
+	// BatchValues renders UPDATE t SET col = v.col FROM (VALUES (CAST(? AS type), ...)) AS v (key, col) WHERE t.key = v.key
+	// (PostgreSQL), the types of all keys and columns are set with BatchTypes
+	BatchValues
+	// BatchUpsert renders INSERT ... ON DUPLICATE KEY UPDATE col = VALUES(col) (MySQL),
+	// rows missing in the table are inserted
+	BatchUpsert
+)
+
+// UpdateRows updates a batch of rows identified by keys columns (any comparable values, e.g. strings or UUIDs)
+// with a single statement. Rows is a slice of Pairs, maps or structs (fields are mapped by `db:"col"` tag
+// or by their snake cased names, `db:"-"` skips a field), all rows must have the same columns.
+// Where conditions of the query are kept and the keys are added to them, so only the rows of the batch are scanned
+func (r *DB) UpdateRows(keys []string, rows interface{}, strategy BatchStrategy) (query string, values []interface{}, err error) {
+	builder := r.Builder
+	if builder.table == "" {
+		return "", nil, ErrNoTable
+	}
+	if len(keys) == 0 {
+		return "", nil, ErrNoBatchKeys
+	}
+
+	r.applyScopes()
+
+	batch, err := rowsOf(rows)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cols, err := batchColumns(keys, batch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch strategy {
+	case BatchCase:
+		values = builder.writeCaseBatch(keys, cols, batch)
+	case BatchValues:
+		if builder.dialect != DialectPostgres {
+			return "", nil, fmt.Errorf("%w: VALUES on %s", ErrBatchStrategyUnsupported, builder.dialect)
+		}
+		for _, col := range append(append([]string{}, keys...), cols...) {
+			typ, ok := builder.batchTypes[col]
+			if !ok {
+				return "", nil, fmt.Errorf("%w: %q", ErrBatchTypeMissing, col)
+			}
+			if !batchType.MatchString(typ) {
+				return "", nil, fmt.Errorf("%w: %q of %q", ErrInvalidBatchType, typ, col)
+			}
+		}
+		values = builder.writeValuesBatch(keys, cols, batch)
+	case BatchUpsert:
+		if !isMySQL(builder.dialect) {
+			return "", nil, fmt.Errorf("%w: ON DUPLICATE KEY on %s", ErrBatchStrategyUnsupported, builder.dialect)
+		}
+
+		data := make([]map[string]interface{}, len(batch))
+		for i, row := range batch {
+			data[i] = make(map[string]interface{}, len(row))
+			for _, p := range row {
+				data[i][p.Column] = p.Value
+			}
+		}
+		return r.UpsertBatch(data, keys, cols)
+	default:
+		return "", nil, ErrBatchStrategyUnsupported
+	}
+
+	return r.built(values)
+}
+
+// BatchTypes sets the column types BatchValues casts the values to, PostgreSQL types untyped VALUES params
+// as text otherwise, so comparing them with the table columns fails, ex.:
+//
+//	db.Table("users").BatchTypes(map[string]string{"id": "bigint", "name": "text"}).UpdateRows([]string{"id"}, rows, BatchValues)
+func (r *DB) BatchTypes(types map[string]string) *DB {
+	r.Builder.batchTypes = types
+	return r
+}
+
+// batchColumns returns the columns to update checking that all rows have the keys and the same columns
+func batchColumns(keys []string, batch []Pairs) ([]string, error) {
+	if len(batch) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	var cols []string
+	for _, p := range batch[0] {
+		if !inStrings(keys, p.Column) {
+			cols = append(cols, p.Column)
+		}
+	}
+	if len(cols) == 0 {
+		return nil, ErrNoBatchColumns
+	}
+
+	for i, row := range batch {
+		if len(row) != len(cols)+len(keys) {
+			return nil, fmt.Errorf("%w: row %d", ErrBatchColumnsMismatch, i)
+		}
+
+		for _, key := range keys {
+			if _, ok := row.get(key); !ok {
+				return nil, fmt.Errorf("%w: %q in row %d", ErrBatchKeyMissing, key, i)
+			}
+		}
+		for _, col := range cols {
+			if _, ok := row.get(col); !ok {
+				return nil, fmt.Errorf("%w: %q in row %d", ErrBatchColumnsMismatch, col, i)
+			}
+		}
+	}
+
+	return cols, nil
+}
+
+// writeCaseBatch writes UPDATE ... SET col = CASE ... END stmt limited to the keys of the batch
+func (r *builder) writeCaseBatch(keys, cols []string, batch []Pairs) (values []interface{}) {
+	r.WriteString("UPDATE").Pad().Ident(r.table).Pad().WriteString("SET").Pad()
+
+	for i, col := range cols {
+		if i > 0 {
+			r.Comma()
+		}
+
+		r.Ident(col).WriteOp(OpEQ).WriteString("CASE")
+		for _, row := range batch {
+			r.Pad().WriteString("WHEN").Pad()
+			for k, key := range keys {
+				if k > 0 {
+					r.WriteString(and)
+				}
+				v, _ := row.get(key)
+				r.Ident(key).WriteOp(OpEQ).WriteString("?")
+				values = append(values, v)
+			}
+
+			v, _ := row.get(col)
+			binding, args := r.bind(v)
+			r.Pad().WriteString("THEN").Pad().WriteString(binding)
+			values = append(values, args...)
+		}
+		r.Pad().WriteString("ELSE").Pad().Ident(col).Pad().WriteString("END")
+	}
+
+	r.andWhereGroup(func(s *sqlBuilder) {
+		if len(keys) == 1 {
+			s.IdentPoint(r.table).Ident(keys[0]).WriteOp(OpIn).Nested(func(s *sqlBuilder) {
+				for i, row := range batch {
+					if i > 0 {
+						s.Comma()
+					}
+					v, _ := row.get(keys[0])
+					s.Arg(v)
+				}
+			})
+			return
+		}
+
+		// (k1 = ? AND k2 = ?) OR (...) is understood by every dialect unlike row values
+		s.Nested(func(s *sqlBuilder) {
+			for i, row := range batch {
+				if i > 0 {
+					s.WriteString(or)
+				}
+				s.Nested(func(s *sqlBuilder) {
+					for k, key := range keys {
+						if k > 0 {
+							s.WriteString(and)
+						}
+						v, _ := row.get(key)
+						s.IdentPoint(r.table).Ident(key).WriteOp(OpEQ).Arg(v)
+					}
+				})
+			}
+		})
+	})
+
+	r.composeOutput(outputInserted)
+	r.WriteString(r.where.String())
+	r.composeReturning("UPDATE")
+	values = append(values, r.where.args...)
+	return
+}
+
+// writeValuesBatch writes PostgreSQL UPDATE ... FROM (VALUES ...) stmt
+func (r *builder) writeValuesBatch(keys, cols []string, batch []Pairs) (values []interface{}) {
+	const alias = "v"
+	all := append(append([]string{}, keys...), cols...)
+
+	r.WriteString("UPDATE").Pad().Ident(r.table).Pad().WriteString("SET").Pad()
+	for i, col := range cols {
+		if i > 0 {
+			r.Comma()
+		}
+		r.Ident(col).WriteOp(OpEQ).IdentPoint(alias).Ident(col)
+	}
+
+	r.Pad().WriteString("FROM").Pad().WriteByte('(').WriteString("VALUES").Pad()
+	for i, row := range batch {
+		if i > 0 {
+			r.Comma()
+		}
+		r.Nested(func(s *sqlBuilder) {
+			for k, col := range all {
+				if k > 0 {
+					s.Comma()
+				}
+				v, _ := row.get(col)
+				binding, args := s.bind(v)
+				s.WriteString("CAST(").WriteString(binding).Pad().WriteString("AS").Pad().WriteString(r.batchTypes[col]).WriteByte(')')
+				values = append(values, args...)
+			}
+		})
+	}
+	r.WriteByte(')').Pad().WriteString("AS").Pad().Ident(alias).Pad().Nested(func(s *sqlBuilder) {
+		s.idents(all)
+	})
+
+	r.WriteString(where)
+	for i, key := range keys {
+		if i > 0 {
+			r.WriteString(and)
+		}
+		r.IdentPoint(r.table).Ident(key).WriteOp(OpEQ).IdentPoint(alias).Ident(key)
+	}
+	if r.where.Len() > 0 {
+		r.WriteString(and).WriteByte('(').WriteString(strings.TrimPrefix(r.where.String(), where)).WriteByte(')')
+		values = append(values, r.where.args...)
+	}
+	r.composeReturning("UPDATE")
+
+	return
+}
+
+// UpdateBatch updates rows matching where key columns values with the update columns values of the same index.
+//
+// Deprecated: use UpdateRows, which accepts keys of any type; on a mismatch UpdateBatch returns an empty query
+// and sets the error returned by Err
+func (r *DB) UpdateBatch(where map[string][]int, update map[string][]interface{}) (query string, values []interface{}) {
+	keys, cols := sortedKeys(where), sortedKeys(update)
+	if len(keys) == 0 || len(cols) == 0 {
+		r.Builder.setErr(ErrEmptyBatch)
+		return
+	}
+
+	n := len(where[keys[0]])
+	batch := make([]Pairs, n)
+	for _, key := range keys {
+		if len(where[key]) != n {
+			r.Builder.setErr(fmt.Errorf("%w: %q", ErrBatchLengthMismatch, key))
+			return
+		}
+		for i, v := range where[key] {
+			batch[i] = append(batch[i], Pair{Column: key, Value: v})
+		}
+	}
+	for _, col := range cols {
+		if len(update[col]) != n {
+			r.Builder.setErr(fmt.Errorf("%w: %q", ErrBatchLengthMismatch, col))
+			return
+		}
+		for i, v := range update[col] {
+			batch[i] = append(batch[i], Pair{Column: col, Value: v})
+		}
+	}
+
+	query, values, err := r.UpdateRows(keys, batch, BatchCase)
+	if err != nil {
+		r.Builder.setErr(err)
+	}
+	return
+}
+
+// rowsOf converts a slice of Pairs, maps or structs to Pairs
+func rowsOf(rows interface{}) ([]Pairs, error) {
+	switch rs := rows.(type) {
+	case []Pairs:
+		for i, row := range rs {
+			if err := row.Err(); err != nil {
+				return nil, fmt.Errorf("%w in row %d", err, i)
+			}
+		}
+		return rs, nil
+	case []map[string]interface{}:
+		batch := make([]Pairs, len(rs))
+		for i, row := range rs {
+			batch[i] = pairsOf(row)
+		}
+		return batch, nil
+	}
+
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return nil, ErrInvalidBatchRows
+	}
+
+	batch := make([]Pairs, v.Len())
+	for i := range batch {
+		row := reflect.Indirect(v.Index(i))
+		if row.Kind() != reflect.Struct {
+			return nil, ErrInvalidBatchRows
+		}
+		batch[i] = structPairs(row, nil)
+	}
+	return batch, nil
+}
+
+// structPairs appends the exported fields of struct value to row
+func structPairs(v reflect.Value, row Pairs) Pairs {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			row = structPairs(v.Field(i), row)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		col := f.Tag.Get("db")
+		if col == "-" {
+			continue
+		}
+		if col == "" {
+			col = snakeCase(f.Name)
+		}
+		row = append(row, Pair{Column: col, Value: v.Field(i).Interface()})
+	}
+	return row
+}
+
+// snakeCase converts Go field name to column name, ex.: UserID -> user_id
+func snakeCase(name string) string {
+	rs := []rune(name)
+	sb := strings.Builder{}
+	for i, c := range rs {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) {
+				sb.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
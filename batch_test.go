@@ -0,0 +1,85 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type batchUser struct {
+	ID      string `db:"uuid"`
+	Name    string
+	Skipped int `db:"-"`
+}
+
+func TestDB_UpdateRowsCase(t *testing.T) {
+	rows := []batchUser{{ID: "a", Name: "foo"}, {ID: "b", Name: "bar"}}
+	query, values, err := newDB(conn).Table("users").Where("active", OpEQ, 1).UpdateRows([]string{"uuid"}, rows, BatchCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name` = CASE WHEN `uuid` = ? THEN ? WHEN `uuid` = ? THEN ? ELSE `name` END"+
+		" WHERE (`users`.`active` = ?) AND `users`.`uuid` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{"a", "foo", "b", "bar", 1, "a", "b"}, values)
+}
+
+func TestDB_UpdateRowsCompositeKey(t *testing.T) {
+	rows := []Pairs{
+		Cols("org", "id", "name").Values(1, 10, "foo"),
+		Cols("org", "id", "name").Values(2, 10, "bar"),
+	}
+	query, values, err := newDB(conn).Table("users").UpdateRows([]string{"org", "id"}, rows, BatchCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name` = CASE WHEN `org` = ? AND `id` = ? THEN ? WHEN `org` = ? AND `id` = ? THEN ? ELSE `name` END"+
+		" WHERE ((`users`.`org` = ? AND `users`.`id` = ?) OR (`users`.`org` = ? AND `users`.`id` = ?))", query)
+	assert.Equal(t, []interface{}{1, 10, "foo", 2, 10, "bar", 1, 10, 2, 10}, values)
+}
+
+func TestDB_UpdateRowsValues(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "foo"}, {"id": 2, "name": "bar"}}
+	types := map[string]string{"id": "bigint", "name": "varchar(64)"}
+	query, values, err := newDB(&Connection{driver: "postgres"}).Table("users").BatchTypes(types).UpdateRows([]string{"id"}, rows, BatchValues)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name" = "v"."name" FROM (VALUES (CAST(? AS bigint), CAST(? AS varchar(64))),`+
+		` (CAST(? AS bigint), CAST(? AS varchar(64)))) AS "v" ("id", "name") WHERE "users"."id" = "v"."id"`, query)
+	assert.Equal(t, []interface{}{1, "foo", 2, "bar"}, values)
+
+	_, _, err = newDB(&Connection{driver: "postgres"}).Table("users").UpdateRows([]string{"id"}, rows, BatchValues)
+	assert.ErrorIs(t, err, ErrBatchTypeMissing)
+
+	types = map[string]string{"id": "bigint", "name": "text) FROM users; --"}
+	_, _, err = newDB(&Connection{driver: "postgres"}).Table("users").BatchTypes(types).UpdateRows([]string{"id"}, rows, BatchValues)
+	assert.ErrorIs(t, err, ErrInvalidBatchType)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, rows, BatchValues)
+	assert.ErrorIs(t, err, ErrBatchStrategyUnsupported)
+}
+
+func TestDB_UpdateRowsUpsert(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "foo"}, {"id": 2, "name": "bar"}}
+	query, values, err := newDB(conn).Table("users").UpdateRows([]string{"id"}, rows, BatchUpsert)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", query)
+	assert.Equal(t, []interface{}{1, "foo", 2, "bar"}, values)
+}
+
+func TestDB_UpdateRowsErrors(t *testing.T) {
+	_, _, err := newDB(conn).Table("users").UpdateRows(nil, []Pairs{}, BatchCase)
+	assert.ErrorIs(t, err, ErrNoBatchKeys)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, []Pairs{}, BatchCase)
+	assert.ErrorIs(t, err, ErrEmptyBatch)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, []Pairs{Cols("name").Values("foo")}, BatchCase)
+	assert.ErrorIs(t, err, ErrBatchColumnsMismatch)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, []Pairs{Cols("id").Values(1)}, BatchCase)
+	assert.ErrorIs(t, err, ErrNoBatchColumns)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, []Pairs{
+		Cols("id", "name").Values(1, "foo"),
+		Cols("id", "title").Values(2, "bar"),
+	}, BatchCase)
+	assert.ErrorIs(t, err, ErrBatchColumnsMismatch)
+
+	_, _, err = newDB(conn).Table("users").UpdateRows([]string{"id"}, []int{1}, BatchCase)
+	assert.ErrorIs(t, err, ErrInvalidBatchRows)
+}
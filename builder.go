@@ -1,12 +1,14 @@
 package buildsqlx
 
 import (
-	"log"
+	"fmt"
 	"os"
-
-	"github.com/huandu/go-clone"
+	"strings"
 )
 
+// comparison operators join conditions may use
+var joinOps = []string{"=", "<>", "!=", "<", "<=", ">", ">="}
+
 const (
 	joinInner     = "INNER"
 	joinLeft      = "LEFT"
@@ -18,6 +20,13 @@ const (
 	or            = " OR "
 )
 
+// join is a joined table with its ON condition
+type join struct {
+	kind  string
+	table string
+	on    string
+}
+
 // orderBy sort
 type orderBy struct {
 	Column    string
@@ -27,30 +36,83 @@ type orderBy struct {
 // inner type to build qualified sql
 type builder struct {
 	sqlBuilder
-	where         *sqlBuilder
-	table         string
-	from          string
-	join          []string
-	orderBy       []*orderBy
-	orderByRaw    *string
-	groupBy       string
-	having        *sqlBuilder
-	columns       []string
-	union         []string
-	isUnionAll    bool
-	offset        int64
-	limit         int64
-	lockForUpdate *string
+	where      *sqlBuilder
+	table      string
+	from       string
+	join       []*join
+	orderBy    []*orderBy
+	orderByRaw *string
+	groupBy    []string
+	having     *sqlBuilder
+	columns    []Expr
+	union      []string
+	unionArgs  []interface{}
+	isUnionAll bool
+	distinct   bool
+	offset     int64
+	limit      int64
+	lock       *lock
+	indexHints []*indexHint
+	hints      []string
+	rowAlias   string
+	returning  []string
+	batchTypes map[string]string
+	// global scopes disabled by name or all of them, scoped is set once they are applied
+	withoutScopes map[string]struct{}
+	noScopes      bool
+	scoped        bool
 }
 
 func newBuilder() *builder {
 	return &builder{
-		columns: []string{"*"},
+		columns: []Expr{Col("*")},
 	}
 }
 
-func deepClone(b *builder) *builder {
-	return clone.Slowly(b).(*builder)
+// fork returns a deep copy of the builder with an empty output buffer, so that a derived query
+// can be rendered without touching the original. The clauses are copied without reflection,
+// the values they hold (bindings, allowed columns) are shared as they are never modified in place
+func (r *builder) fork() *builder {
+	b := *r
+	b.sqlBuilder = *r.sqlBuilder.derive()
+	if r.where != nil {
+		b.where = r.where.clone()
+	}
+	if r.having != nil {
+		b.having = r.having.clone()
+	}
+
+	b.join = make([]*join, len(r.join))
+	for i, j := range r.join {
+		jc := *j
+		b.join[i] = &jc
+	}
+	b.orderBy = make([]*orderBy, len(r.orderBy))
+	for i, o := range r.orderBy {
+		oc := *o
+		b.orderBy[i] = &oc
+	}
+	if r.orderByRaw != nil {
+		raw := *r.orderByRaw
+		b.orderByRaw = &raw
+	}
+	if r.lock != nil {
+		l := *r.lock
+		l.of = append([]string(nil), r.lock.of...)
+		b.lock = &l
+	}
+	b.indexHints = make([]*indexHint, len(r.indexHints))
+	for i, h := range r.indexHints {
+		b.indexHints[i] = &indexHint{kind: h.kind, indexes: append([]string(nil), h.indexes...)}
+	}
+
+	b.groupBy = append([]string(nil), r.groupBy...)
+	b.columns = append([]Expr(nil), r.columns...)
+	b.union = append([]string(nil), r.union...)
+	b.unionArgs = append([]interface{}(nil), r.unionArgs...)
+	b.hints = append([]string(nil), r.hints...)
+	b.returning = append([]string(nil), r.returning...)
+	return &b
 }
 
 // Target returns db driver
@@ -58,6 +120,43 @@ func (r *DB) Target() string {
 	return r.Conn.driver
 }
 
+// Err returns the first error occurred while building the statement
+func (r *DB) Err() error {
+	for _, b := range []*sqlBuilder{&r.Builder.sqlBuilder, r.Builder.where, r.Builder.having} {
+		if b != nil && b.err != nil {
+			return b.err
+		}
+	}
+	return nil
+}
+
+// built returns the statement rendered to the builder unless an error occurred while building it
+func (r *DB) built(values []interface{}) (string, []interface{}, error) {
+	if err := r.Err(); err != nil {
+		return "", nil, err
+	}
+	return r.Builder.String(), values, nil
+}
+
+// ToSQL returns the select query, its bindings and the first error occurred while building it
+func (r *DB) ToSQL() (query string, values []interface{}, err error) {
+	query, values = r.Query()
+	if err = r.Err(); err != nil {
+		return "", nil, err
+	}
+	return
+}
+
+// Clone returns an independent copy of the query, so that a base query can be
+// extended into list, count and other variants without affecting each other, ex.:
+//
+//	base := db.Table("posts").Where("tenant_id", buildsqlx.OpEQ, tenant).LeftJoin("users", "users.id", "=", "posts.user_id")
+//	list, values := base.Clone().OrderBy("posts.id", "DESC").Limit(20).Query()
+//	count, values := base.Clone().Count()
+func (r *DB) Clone() *DB {
+	return &DB{Builder: r.Builder.fork(), Conn: r.Conn}
+}
+
 // Table appends table name to sql query
 func (r *DB) Table(table string) *DB {
 	// reset before constructing again
@@ -68,37 +167,80 @@ func (r *DB) Table(table string) *DB {
 
 // resets all builder elements to prepare them for next round
 func (r *DB) reset() {
-	r.Builder.sqlBuilder = sqlBuilder{}
+	r.Builder.sqlBuilder = sqlBuilder{dialect: r.Builder.dialect}
 	r.Builder.table = ""
-	r.Builder.columns = []string{"*"}
-	r.Builder.where = &sqlBuilder{}
-	r.Builder.groupBy = ""
-	r.Builder.having = &sqlBuilder{}
+	r.Builder.columns = []Expr{Col("*")}
+	r.Builder.where = &sqlBuilder{dialect: r.Builder.dialect}
+	r.Builder.groupBy = nil
+	r.Builder.having = &sqlBuilder{dialect: r.Builder.dialect}
 	r.Builder.orderBy = make([]*orderBy, 0)
 	r.Builder.offset = 0
 	r.Builder.limit = 0
-	r.Builder.join = []string{}
+	r.Builder.join = nil
 	r.Builder.from = ""
 	// union不初始化
 	// r.Builder.union = []string{}
 	r.Builder.isUnionAll = false
-	r.Builder.lockForUpdate = nil
+	r.Builder.distinct = false
+	r.Builder.lock = nil
+	r.Builder.indexHints = nil
+	r.Builder.hints = nil
+	r.Builder.rowAlias = ""
+	r.Builder.returning = nil
+	r.Builder.batchTypes = nil
 	r.Builder.orderByRaw = nil
+	r.Builder.withoutScopes = nil
+	r.Builder.noScopes = false
+	r.Builder.scoped = false
 }
 
-// Select accepts columns to select from a table
+// Select accepts columns to select from a table, columns are quoted as identifiers
+// and may be qualified with the table or aliased, ex.: Select("users.name AS author", "posts.*").
+// Use SelectRaw to select expressions
 func (r *DB) Select(args ...string) *DB {
-	r.Builder.columns = []string{}
-	r.Builder.columns = append(r.Builder.columns, args...)
+	r.Builder.columns = make([]Expr, 0, len(args))
+	return r.AddSelect(args...)
+}
+
+// AllowColumns restricts the columns the query may refer to by Select, Where, Having, OrderBy and GroupBy
+// to the ones given, so that column names coming from user input can't reach the statement.
+// Call it right after Table, any other column is recorded as ErrColumnNotAllowed error
+func (r *DB) AllowColumns(cols ...string) *DB {
+	allowed := make(map[string]struct{}, len(cols))
+	for _, col := range cols {
+		allowed[col] = struct{}{}
+	}
+
+	r.Builder.allowed = allowed
+	r.Builder.where.allowed = allowed
+	r.Builder.having.allowed = allowed
 	return r
 }
 
-// OrderBy adds ORDER BY expression to SQL stmt
+// Distinct adds DISTINCT to SELECT stmt
+func (r *DB) Distinct() *DB {
+	r.Builder.distinct = true
+	return r
+}
+
+// OrderBy adds ORDER BY expression to SQL stmt, direction is either ASC (the default if empty) or DESC
 func (r *DB) OrderBy(column string, direction string) *DB {
-	r.Builder.orderBy = append(r.Builder.orderBy, &orderBy{
-		Column:    column,
-		Direction: direction,
-	})
+	dir := strings.ToUpper(strings.TrimSpace(direction))
+	switch dir {
+	case "":
+		dir = "ASC"
+	case "ASC", "DESC":
+	default:
+		r.Builder.setErr(fmt.Errorf("%w: %q", ErrInvalidDirection, direction))
+		return r
+	}
+
+	if r.Builder.allow(column) {
+		r.Builder.orderBy = append(r.Builder.orderBy, &orderBy{
+			Column:    column,
+			Direction: dir,
+		})
+	}
 	return r
 }
 
@@ -114,17 +256,23 @@ func (r *DB) InRandomOrder() *DB {
 	return r
 }
 
-// GroupBy adds GROUP BY expression to SQL stmt
+// GroupBy adds GROUP BY clause to SQL stmt, expr is a comma separated list of columns
 func (r *DB) GroupBy(expr string) *DB {
-	r.Builder.groupBy = expr
+	var cols []string
+	for _, col := range strings.Split(expr, ",") {
+		if col = strings.TrimSpace(col); col != "" && r.Builder.allow(col) {
+			cols = append(cols, col)
+		}
+	}
+
+	r.Builder.groupBy = cols
 	return r
 }
 
 // Having similar to Where but used with GroupBy to apply over the grouped results
 func (r *DB) Having(col string, op Op, val interface{}) *DB {
 	r.Builder.having.
-		IdentPoint(r.Builder.table).
-		Ident(col).
+		Column(r.Builder.table, col).
 		WriteOp(op).
 		Arg(val)
 	return r
@@ -132,29 +280,44 @@ func (r *DB) Having(col string, op Op, val interface{}) *DB {
 
 // AddSelect accepts additional columns to select from a table
 func (r *DB) AddSelect(args ...string) *DB {
-	r.Builder.columns = append(r.Builder.columns, args...)
+	for _, arg := range args {
+		col := selectColumn(arg)
+		if col.col == "*" || strings.HasSuffix(col.col, ".*") || r.Builder.allow(col.col) {
+			r.Builder.columns = append(r.Builder.columns, col)
+		}
+	}
 	return r
 }
 
-// SelectRaw accepts custom string to select from a table
+// SelectRaw accepts custom string to select from a table, it's rendered as is
+// so it must never contain user input
 func (r *DB) SelectRaw(raw string) *DB {
-	r.Builder.columns = []string{raw}
+	r.Builder.columns = []Expr{Raw(raw)}
 	return r
 }
 
+// selectColumn parses selected column with optional alias: col [AS alias]
+func selectColumn(col string) Expr {
+	fields := strings.Fields(col)
+	if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+		return Expr{col: fields[0], alias: fields[2]}
+	}
+	return Col(strings.TrimSpace(col))
+}
+
 // InnerJoin joins tables by getting elements if found in both
 func (r *DB) InnerJoin(table, left, operator, right string) *DB {
-	return r.buildJoin(joinInner, table, left+operator+right)
+	return r.joinOn(joinInner, table, left, operator, right)
 }
 
 // LeftJoin joins tables by getting elements from left without those that null on the right
 func (r *DB) LeftJoin(table, left, operator, right string) *DB {
-	return r.buildJoin(joinLeft, table, left+operator+right)
+	return r.joinOn(joinLeft, table, left, operator, right)
 }
 
 // RightJoin joins tables by getting elements from right without those that null on the left
 func (r *DB) RightJoin(table, left, operator, right string) *DB {
-	return r.buildJoin(joinRight, table, left+operator+right)
+	return r.joinOn(joinRight, table, left, operator, right)
 }
 
 // CrossJoin joins tables by getting intersection of sets
@@ -165,17 +328,43 @@ func (r *DB) RightJoin(table, left, operator, right string) *DB {
 
 // FullJoin joins tables by getting all elements of both sets
 func (r *DB) FullJoin(table, left, operator, right string) *DB {
-	return r.buildJoin(joinFull, table, left+operator+right)
+	return r.joinOn(joinFull, table, left, operator, right)
 }
 
 // FullOuterJoin joins tables by getting an outer sets
 func (r *DB) FullOuterJoin(table, left, operator, right string) *DB {
-	return r.buildJoin(joinFullOuter, table, left+operator+right)
+	return r.joinOn(joinFullOuter, table, left, operator, right)
+}
+
+// joinOn adds the join of the table on left operator right condition, table and columns are quoted,
+// table may be aliased, ex.: "users AS u"
+func (r *DB) joinOn(kind, table, left, operator, right string) *DB {
+	op := strings.TrimSpace(operator)
+	if !inStrings(joinOps, op) {
+		r.Builder.setErr(fmt.Errorf("%w: %q in JOIN", ErrInvalidOp, operator))
+		return r
+	}
+
+	var tbl string
+	switch fields := strings.Fields(table); {
+	case len(fields) == 2:
+		tbl = r.Builder.Quote(fields[0]) + " " + r.Builder.Quote(fields[1])
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		tbl = r.Builder.Quote(fields[0]) + " AS " + r.Builder.Quote(fields[2])
+	default:
+		tbl = r.Builder.Quote(strings.TrimSpace(table))
+	}
+
+	return r.buildJoin(kind, tbl, r.Builder.Quote(strings.TrimSpace(left))+" "+op+" "+r.Builder.Quote(strings.TrimSpace(right)))
 }
 
 // Union joins multiple queries omitting duplicate records
 func (r *DB) Union() *DB {
+	r.applyScopes()
 	r.Builder.union = append(r.Builder.union, r.Builder.buildSelect())
+	// the bindings of the unioned query are placed before the ones of the next query
+	r.Builder.unionArgs = append(r.Builder.unionArgs, r.Builder.where.args...)
+	r.Builder.unionArgs = append(r.Builder.unionArgs, r.Builder.having.args...)
 	return r
 }
 
@@ -187,23 +376,48 @@ func (r *DB) UnionAll() *DB {
 }
 
 func (r *DB) buildJoin(joinType, table, on string) *DB {
-	r.Builder.join = append(r.Builder.join, " "+joinType+" JOIN "+table+" ON "+on+" ")
+	r.Builder.join = append(r.Builder.join, &join{kind: joinType, table: table, on: on})
 	return r
 }
 
+// whereOp starts the next condition of WHERE clause with the logical operator,
+// the first condition starts the clause itself
+func (r *builder) whereOp(op string) *sqlBuilder {
+	if r.where.Len() == 0 {
+		return r.where.WriteString(where)
+	}
+	return r.where.WriteString(op)
+}
+
+// andWhereGroup appends the condition to WHERE clause with AND logical operator,
+// existing conditions are wrapped with parentheses so that their ORs don't leak into it
+func (r *builder) andWhereGroup(cond func(*sqlBuilder)) {
+	w := r.where.derive()
+	w.WriteString(where)
+	if r.where.Len() > 0 {
+		w.WriteByte('(').
+			WriteString(strings.TrimPrefix(r.where.String(), where)).
+			WriteByte(')').
+			WriteString(and)
+		w.args = append(w.args, r.where.args...)
+	}
+	cond(w)
+	r.where = w
+}
+
 // Where accepts left operand-operator-right operand to apply them to where clause
 func (r *DB) WhereRaw(raw string, val ...interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
+	r.Builder.whereOp(and).
 		WriteString(raw).
 		Params(val...)
 	return r
 }
 
-// Where accepts left operand-operator-right operand to apply them to where clause
+// Where accepts left operand-operator-right operand to apply them to where clause,
+// the conditions following the first one are joined with AND
 func (r *DB) Where(col string, op Op, val interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(op).
 		Arg(val)
 	return r
@@ -212,10 +426,8 @@ func (r *DB) Where(col string, op Op, val interface{}) *DB {
 // AndWhere accepts left operand-operator-right operand to apply them to where clause
 // with AND logical operator
 func (r *DB) AndWhere(col string, op Op, val interface{}) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(op).
 		Arg(val)
 	return r
@@ -224,10 +436,8 @@ func (r *DB) AndWhere(col string, op Op, val interface{}) *DB {
 // OrWhere accepts left operand-operator-right operand to apply them to where clause
 // with OR logical operator
 func (r *DB) OrWhere(col string, op Op, val interface{}) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(op).
 		Arg(val)
 	return r
@@ -235,9 +445,8 @@ func (r *DB) OrWhere(col string, op Op, val interface{}) *DB {
 
 // WhereBetween sets the clause BETWEEN 2 values
 func (r *DB) WhereBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -247,10 +456,8 @@ func (r *DB) WhereBetween(col string, val1, val2 interface{}) *DB {
 
 // OrWhereBetween sets the clause OR BETWEEN 2 values
 func (r *DB) OrWhereBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -260,10 +467,8 @@ func (r *DB) OrWhereBetween(col string, val1, val2 interface{}) *DB {
 
 // AndWhereBetween sets the clause AND BETWEEN 2 values
 func (r *DB) AndWhereBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -273,9 +478,8 @@ func (r *DB) AndWhereBetween(col string, val1, val2 interface{}) *DB {
 
 // WhereNotBetween sets the clause NOT BETWEEN 2 values
 func (r *DB) WhereNotBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -285,10 +489,8 @@ func (r *DB) WhereNotBetween(col string, val1, val2 interface{}) *DB {
 
 // OrWhereNotBetween sets the clause OR BETWEEN 2 values
 func (r *DB) OrWhereNotBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -298,10 +500,8 @@ func (r *DB) OrWhereNotBetween(col string, val1, val2 interface{}) *DB {
 
 // AndWhereNotBetween sets the clause AND BETWEEN 2 values
 func (r *DB) AndWhereNotBetween(col string, val1, val2 interface{}) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotBetween).
 		Arg(val1).Pad().
 		WriteString("AND").Pad().
@@ -323,9 +523,8 @@ func (r *DB) Limit(lim int64) *DB {
 
 // WhereIn appends IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) WhereIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -335,9 +534,8 @@ func (r *DB) WhereIn(col string, in ...interface{}) *DB {
 
 // WhereNotIn appends NOT IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) WhereNotIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -347,10 +545,8 @@ func (r *DB) WhereNotIn(col string, in ...interface{}) *DB {
 
 // OrWhereIn appends OR IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) OrWhereIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -360,10 +556,8 @@ func (r *DB) OrWhereIn(col string, in ...interface{}) *DB {
 
 // OrWhereNotIn appends OR NOT IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) OrWhereNotIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -373,10 +567,8 @@ func (r *DB) OrWhereNotIn(col string, in ...interface{}) *DB {
 
 // AndWhereIn appends OR IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) AndWhereIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -386,10 +578,8 @@ func (r *DB) AndWhereIn(col string, in ...interface{}) *DB {
 
 // AndWhereNotIn appends OR NOT IN (val1, val2, val3...) stmt to WHERE clause
 func (r *DB) AndWhereNotIn(col string, in ...interface{}) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotIn).
 		Nested(func(b *sqlBuilder) {
 			b.Args(in...)
@@ -399,67 +589,56 @@ func (r *DB) AndWhereNotIn(col string, in ...interface{}) *DB {
 
 // WhereNull appends col IS NULL stmt to WHERE clause
 func (r *DB) WhereNull(col string) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpIsNull)
 	return r
 }
 
 // WhereNotNull appends col IS NOT NULL stmt to WHERE clause
 func (r *DB) WhereNotNull(col string) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotNull)
 	return r
 }
 
 // OrWhereNull appends col IS NULL stmt to WHERE clause
 func (r *DB) OrWhereNull(col string) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpIsNull)
 	return r
 }
 
 // OrWhereNotNull appends col IS NOT NULL stmt to WHERE clause
 func (r *DB) OrWhereNotNull(col string) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotNull)
 	return r
 }
 
 // AndWhereNull appends col IS NULL stmt to WHERE clause
 func (r *DB) AndWhereNull(col string) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpIsNull)
 	return r
 }
 
 // AndWhereNotNull appends col IS NOT NULL stmt to WHERE clause
 func (r *DB) AndWhereNotNull(col string) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotNull)
 	return r
 }
 
 // WhereLike appends col is LIKE pattern stmt to WHERE clause
 func (r *DB) WhereLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpLike).
 		Args(pattern)
 	return r
@@ -467,10 +646,8 @@ func (r *DB) WhereLike(col string, pattern string) *DB {
 
 // OrWhereLike appends col is LIKE pattern stmt to WHERE clause
 func (r *DB) OrWhereLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpLike).
 		Args(pattern)
 	return r
@@ -478,10 +655,8 @@ func (r *DB) OrWhereLike(col string, pattern string) *DB {
 
 // AndWhereLike appends col is LIKE pattern stmt to WHERE clause
 func (r *DB) AndWhereLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpLike).
 		Args(pattern)
 	return r
@@ -489,9 +664,8 @@ func (r *DB) AndWhereLike(col string, pattern string) *DB {
 
 // WhereNotLike appends col is NOT LIKE pattern stmt to WHERE clause
 func (r *DB) WhereNotLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(" WHERE ").
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotLike).
 		Args(pattern)
 	return r
@@ -499,10 +673,8 @@ func (r *DB) WhereNotLike(col string, pattern string) *DB {
 
 // OrWhereNotLike appends col is NOT LIKE pattern stmt to WHERE clause
 func (r *DB) OrWhereNotLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(or).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotLike).
 		Args(pattern)
 	return r
@@ -510,10 +682,8 @@ func (r *DB) OrWhereNotLike(col string, pattern string) *DB {
 
 // AndWhereNotLike appends col is NOT LIKE pattern stmt to WHERE clause
 func (r *DB) AndWhereNotLike(col string, pattern string) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().
-		IdentPoint(r.Builder.table).
-		Ident(col).
+	r.Builder.whereOp(and).
+		Column(r.Builder.table, col).
 		WriteOp(OpNotLike).
 		Args(pattern)
 	return r
@@ -521,15 +691,13 @@ func (r *DB) AndWhereNotLike(col string, pattern string) *DB {
 
 // WhereEmpty appends col IS NOT NULL and IS EMPRTY str stmt to WHERE clause
 func (r *DB) WhereEmpty(col string) *DB {
-	r.Builder.where.WriteString(" WHERE ").
+	r.Builder.whereOp(and).
 		Nested(func(sb *sqlBuilder) {
-			sb.IdentPoint(r.Builder.table).
-				Ident(col).
+			sb.Column(r.Builder.table, col).
 				WriteOp(OpEQ).
 				Arg("").
 				WriteString(or).
-				IdentPoint(r.Builder.table).
-				Ident(col).
+				Column(r.Builder.table, col).
 				WriteOp(OpIsNull)
 		})
 	return r
@@ -537,15 +705,12 @@ func (r *DB) WhereEmpty(col string) *DB {
 
 // OrWhereEmpty appends col IS NOT NULL and IS EMPRTY str stmt to WHERE clause
 func (r *DB) OrWhereEmpty(col string) *DB {
-	r.Builder.where.WriteString(or).
-		Pad().Nested(func(sb *sqlBuilder) {
-		sb.IdentPoint(r.Builder.table).
-			Ident(col).
+	r.Builder.whereOp(or).Nested(func(sb *sqlBuilder) {
+		sb.Column(r.Builder.table, col).
 			WriteOp(OpEQ).
 			Arg("").
 			WriteString(or).
-			IdentPoint(r.Builder.table).
-			Ident(col).
+			Column(r.Builder.table, col).
 			WriteOp(OpIsNull)
 	})
 	return r
@@ -553,42 +718,37 @@ func (r *DB) OrWhereEmpty(col string) *DB {
 
 // AndWhereEmpty appends col IS NOT NULL and IS EMPRTY str stmt to WHERE clause
 func (r *DB) AndWhereEmpty(col string) *DB {
-	r.Builder.where.WriteString(and).
-		Pad().Nested(func(sb *sqlBuilder) {
-		sb.IdentPoint(r.Builder.table).
-			Ident(col).
+	r.Builder.whereOp(and).Nested(func(sb *sqlBuilder) {
+		sb.Column(r.Builder.table, col).
 			WriteOp(OpEQ).
 			Arg("").
 			WriteString(or).
-			IdentPoint(r.Builder.table).
-			Ident(col).
+			Column(r.Builder.table, col).
 			WriteOp(OpIsNull)
 	})
 
 	return r
 }
 
-// From prepares sql stmt to set data from another table, ex.:
-// UPDATE employees SET sales_count = sales_count + 1 FROM accounts
+// From adds another table to UPDATE or DELETE stmt, ex.:
+// UPDATE employees SET sales_count = sales_count + 1 FROM accounts WHERE ...
+// It is rendered as UPDATE employees, accounts SET ... on MySQL and as DELETE FROM employees USING accounts on PostgreSQL
 func (r *DB) From(fromTbl string) *DB {
 	r.Builder.from = fromTbl
 	return r
 }
 
-// LockForUpdate locks table/row
-func (r *DB) LockForUpdate() *DB {
-	str := " FOR UPDATE"
-	r.Builder.lockForUpdate = &str
-	return r
-}
-
-// Dump prints raw sql to stdout
+// Dump prints the select query with inlined bindings to stdout, see ToDebugSQL.
+// The error is printed to stderr, use DumpTo to handle it
 func (r *DB) Dump() {
-	log.SetOutput(os.Stdout)
-	log.Println(r.Builder.buildSelect())
+	if err := r.DumpTo(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 }
 
 // Dd prints raw sql to stdout and exit
+//
+// Deprecated: Dd terminates the process, use Dump, DumpTo or LogTo instead
 func (r *DB) Dd() {
 	r.Dump()
 	os.Exit(0)
@@ -0,0 +1,157 @@
+package buildsqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNoQuerier        = errors.New("sql: there was no SetQuerier() call on the connection")
+	ErrInvalidChunkSize = errors.New("sql: chunk size must be greater than zero")
+	ErrChunkKeyMissing  = errors.New("sql: chunk key column is missing in the selected columns")
+)
+
+// Chunk walks all rows matched by the query in chunks of size rows ordered by the "id" column,
+// see ChunkByID
+func (r *DB) Chunk(ctx context.Context, size int64, fn func(rows []map[string]interface{}) error) error {
+	return r.ChunkByID(ctx, "id", size, fn)
+}
+
+// ChunkByID walks all rows matched by the query in chunks of size rows ordered by col,
+// every next chunk is selected by col > last seen value instead of an OFFSET, so the col values must be unique.
+// Col may be qualified with the table name, it must be selected explicitly (optionally aliased) if the query
+// joins other tables, as the last value is read from the rows by its name.
+// Iteration stops on the first error returned by fn, the query or ctx
+func (r *DB) ChunkByID(ctx context.Context, col string, size int64, fn func(rows []map[string]interface{}) error) error {
+	if r.Builder.table == "" {
+		return ErrNoTable
+	}
+	if size < 1 {
+		return ErrInvalidChunkSize
+	}
+	if r.Conn == nil || r.Conn.querier == nil {
+		return ErrNoQuerier
+	}
+
+	key, err := r.Builder.chunkKey(col)
+	if err != nil {
+		return err
+	}
+
+	var last interface{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk := &DB{Builder: r.Builder.fork(), Conn: r.Conn}
+		builder := chunk.Builder
+		builder.orderBy = []*orderBy{{Column: col, Direction: "ASC"}}
+		builder.orderByRaw = nil
+		builder.limit = size
+		builder.offset = 0
+		if last != nil {
+			builder.andWhereGroup(func(s *sqlBuilder) {
+				s.Column(builder.table, col).WriteOp(OpGT).Arg(last)
+			})
+		}
+
+		query, values, err := chunk.ToSQL()
+		if err != nil {
+			return err
+		}
+		rows, err := queryRows(ctx, r.Conn.querier, query, values)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		if err = fn(rows); err != nil {
+			return err
+		}
+		if int64(len(rows)) < size {
+			return nil
+		}
+
+		var ok bool
+		if last, ok = rows[len(rows)-1][key]; !ok {
+			return ErrChunkKeyMissing
+		}
+	}
+}
+
+// chunkKey returns the name col is read from the rows by. Wildcards of the joined tables could return
+// other columns of the same name, so the key must be selected explicitly if the query joins tables
+func (r *builder) chunkKey(col string) (string, error) {
+	qualify := func(c string) string {
+		if strings.Contains(c, ".") {
+			return c
+		}
+		return r.table + "." + c
+	}
+
+	key := col[strings.LastIndexByte(col, '.')+1:]
+	multi := r.isMultiTable()
+	for _, c := range r.columns {
+		switch {
+		case c.sql != "":
+			// raw selections can't be inspected
+			return key, nil
+		case c.col == "*" || strings.HasSuffix(c.col, ".*"):
+			if !multi {
+				return key, nil
+			}
+			return "", fmt.Errorf("%w: %q is ambiguous with %s selected", ErrChunkKeyMissing, col, c.col)
+		}
+	}
+
+	for _, c := range r.columns {
+		if qualify(c.col) != qualify(col) {
+			continue
+		}
+		if c.alias != "" {
+			return c.alias, nil
+		}
+		return key, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrChunkKeyMissing, col)
+}
+
+// queryRows runs the query and scans all resulting rows to column name - value maps
+func queryRows(ctx context.Context, q Querier, query string, values []interface{}) ([]map[string]interface{}, error) {
+	rows, err := q.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []map[string]interface{}
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range vals {
+			dest[i] = &vals[i]
+		}
+
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			row[col] = vals[i]
+		}
+		res = append(res, row)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,141 @@
+package buildsqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver serves ids from 1 to rows to queries seeking by `id` > ? with LIMIT n,
+// it's opened as sql.OpenDB connector so that no driver is registered globally
+type fakeDriver struct {
+	rows    int64
+	queries []string
+}
+
+var fakeLimit = regexp.MustCompile(`LIMIT (\d+)`)
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return d, nil }
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return d, nil }
+
+func (d *fakeDriver) Driver() driver.Driver { return d }
+
+func (d *fakeDriver) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+
+func (d *fakeDriver) Close() error { return nil }
+
+func (d *fakeDriver) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (d *fakeDriver) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d.queries = append(d.queries, query)
+
+	var from int64
+	if strings.Contains(query, "`id` > ?") {
+		from = args[len(args)-1].Value.(int64)
+	}
+	limit, _ := strconv.ParseInt(fakeLimit.FindStringSubmatch(query)[1], 10, 64)
+
+	rows := &fakeRows{}
+	for id := from + 1; id <= d.rows && int64(len(rows.ids)) < limit; id++ {
+		rows.ids = append(rows.ids, id)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	ids []int64
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.ids) == 0 {
+		return io.EOF
+	}
+	dest[0], r.ids = r.ids[0], r.ids[1:]
+	return nil
+}
+
+func newFakeDB(t *testing.T, rows int64) (*DB, *fakeDriver) {
+	drv := &fakeDriver{rows: rows}
+	sqlDB := sql.OpenDB(drv)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	return newDB((&Connection{driver: "mysql"}).SetQuerier(sqlDB)), drv
+}
+
+func TestDB_ChunkByID(t *testing.T) {
+	d, drv := newFakeDB(t, 7)
+
+	var ids []interface{}
+	err := d.Table("users").Where("active", OpEQ, 1).OrderBy("name", "ASC").
+		ChunkByID(context.Background(), "id", 3, func(rows []map[string]interface{}) error {
+			for _, row := range rows {
+				ids = append(ids, row["id"])
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7)}, ids)
+	assert.Equal(t, []string{
+		"SELECT * FROM `users` WHERE `users`.`active` = ? ORDER BY `users`.`id` ASC LIMIT 3",
+		"SELECT * FROM `users` WHERE (`users`.`active` = ?) AND `users`.`id` > ? ORDER BY `users`.`id` ASC LIMIT 3",
+		"SELECT * FROM `users` WHERE (`users`.`active` = ?) AND `users`.`id` > ? ORDER BY `users`.`id` ASC LIMIT 3",
+	}, drv.queries)
+}
+
+func TestDB_ChunkStops(t *testing.T) {
+	d, drv := newFakeDB(t, 10)
+
+	errStop := errors.New("stop")
+	err := d.Table("users").Chunk(context.Background(), 2, func(rows []map[string]interface{}) error {
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Len(t, drv.queries, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = d.Table("users").Chunk(ctx, 2, func(rows []map[string]interface{}) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, drv.queries, 2)
+
+	err = newDB(conn).Table("users").Chunk(context.Background(), 2, nil)
+	assert.ErrorIs(t, err, ErrNoQuerier)
+}
+
+func TestDB_ChunkByIDJoin(t *testing.T) {
+	d, drv := newFakeDB(t, 2)
+
+	var ids []interface{}
+	err := d.Table("users").Select("users.id", "orders.total").InnerJoin("orders", "orders.user_id", "=", "users.id").
+		ChunkByID(context.Background(), "users.id", 1, func(rows []map[string]interface{}) error {
+			ids = append(ids, rows[0]["id"])
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, ids)
+	assert.Equal(t, "SELECT `users`.`id`, `orders`.`total` FROM `users` INNER JOIN `orders` ON `orders`.`user_id` = `users`.`id`"+
+		" WHERE `users`.`id` > ? ORDER BY `users`.`id` ASC LIMIT 1", drv.queries[1])
+
+	err = d.Table("users").InnerJoin("orders", "orders.user_id", "=", "users.id").
+		ChunkByID(context.Background(), "users.id", 1, func(rows []map[string]interface{}) error { return nil })
+	assert.ErrorIs(t, err, ErrChunkKeyMissing)
+
+	err = d.Table("users").Select("name").
+		ChunkByID(context.Background(), "id", 1, func(rows []map[string]interface{}) error { return nil })
+	assert.ErrorIs(t, err, ErrChunkKeyMissing)
+}
@@ -0,0 +1,38 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Clone(t *testing.T) {
+	base := newDB(conn).Table("posts").Select("posts.id", "title").
+		LeftJoin("users", "users.id", "=", "posts.user_id").
+		Where("tenant_id", OpEQ, 7).OrderBy("posts.id", "DESC")
+
+	list, values := base.Clone().AndWhere("status", OpEQ, "draft").Limit(20).LockForUpdate().Query()
+	assert.Equal(t, "SELECT `posts`.`id`, `title` FROM `posts` LEFT JOIN `users` ON `users`.`id` = `posts`.`user_id`"+
+		" WHERE `posts`.`tenant_id` = ? AND `posts`.`status` = ? ORDER BY `posts`.`id` DESC LIMIT 20 FOR UPDATE", list)
+	assert.Equal(t, []interface{}{7, "draft"}, values)
+
+	count, values := base.Clone().Count()
+	assert.Equal(t, "SELECT COUNT(*) FROM `posts` LEFT JOIN `users` ON `users`.`id` = `posts`.`user_id`"+
+		" WHERE `posts`.`tenant_id` = ?", count)
+	assert.Equal(t, []interface{}{7}, values)
+
+	// the base query is intact
+	query, values := base.Query()
+	assert.Equal(t, "SELECT `posts`.`id`, `title` FROM `posts` LEFT JOIN `users` ON `users`.`id` = `posts`.`user_id`"+
+		" WHERE `posts`.`tenant_id` = ? ORDER BY `posts`.`id` DESC", query)
+	assert.Equal(t, []interface{}{7}, values)
+}
+
+func TestDB_CloneKeepsErr(t *testing.T) {
+	base := newDB(conn).Table("posts").AllowColumns("id").Where("secret", OpEQ, 1)
+	_, _, err := base.Clone().ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+
+	_, _, err = newDB(conn).Table("posts").AllowColumns("id").Clone().OrderBy("secret", "ASC").ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+}
@@ -1,6 +1,10 @@
 package buildsqlx
 
-import "sync"
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
 
 var (
 	conn *Connection
@@ -8,9 +12,18 @@ var (
 	once sync.Once
 )
 
+// Querier runs generated queries, it's implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
 // Connection encloses DB struct
 type Connection struct {
-	driver string
+	driver  string
+	querier Querier
+	// global scopes by table
+	mu     sync.RWMutex
+	scopes map[string][]globalScope
 }
 
 // NewConnection returns pre-defined Connection structure
@@ -27,6 +40,12 @@ func NewConnection(driverName string) *Connection {
 	return conn
 }
 
+// SetQuerier sets the database handle used by the methods running queries, e.g. Chunk
+func (c *Connection) SetQuerier(q Querier) *Connection {
+	c.querier = q
+	return c
+}
+
 // DB get a sql builder
 func (c *Connection) DB() *DB {
 	return pool.Get().(*DB)
@@ -41,5 +60,6 @@ type DB struct {
 // newDB constructs default DB structure
 func newDB(c *Connection) *DB {
 	b := newBuilder()
+	b.dialect = dialectOf(c.driver)
 	return &DB{Builder: b, Conn: c}
 }
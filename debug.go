@@ -0,0 +1,103 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrBindingsMismatch is returned when the amount of placeholders differs from the amount of bindings
+var ErrBindingsMismatch = errors.New("sql: placeholders don't match bindings")
+
+// Logger is the logger debug statements are written to, *slog.Logger satisfies it
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// ToDebugSQL returns the select query with the bindings inlined as literals of the dialect.
+// The result is meant for logs and debugging only, never execute it, use ToSQL instead
+func (r *DB) ToDebugSQL() (string, error) {
+	// rendering on a copy keeps the query intact for the following calls
+	d := &DB{Builder: r.Builder.fork(), Conn: r.Conn}
+	query, values, err := d.ToSQL()
+	if err != nil {
+		return "", err
+	}
+
+	return interpolate(r.Builder.dialect, query, values)
+}
+
+// DumpTo writes the debug sql of the select query to w
+func (r *DB) DumpTo(w io.Writer) error {
+	query, err := r.ToDebugSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, query)
+	return err
+}
+
+// LogTo logs the debug sql of the select query with the debug level
+func (r *DB) LogTo(l Logger) {
+	query, err := r.ToDebugSQL()
+	if err != nil {
+		l.Debug("buildsqlx query", "error", err)
+		return
+	}
+
+	l.Debug("buildsqlx query", "sql", query)
+}
+
+// interpolate replaces ? placeholders of the query with the values rendered as literals
+func interpolate(dialect, query string, values []interface{}) (string, error) {
+	res, n, err := replacePlaceholders(dialect, query, func(n int) (string, error) {
+		if n >= len(values) {
+			return "", fmt.Errorf("%w: more than %d placeholders", ErrBindingsMismatch, len(values))
+		}
+		return literal(dialect, values[n])
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if n != len(values) {
+		return "", fmt.Errorf("%w: %d placeholders for %d bindings", ErrBindingsMismatch, n, len(values))
+	}
+	return res, nil
+}
+
+// replacePlaceholders replaces ? placeholders of the query with the strings returned by fn for their indexes,
+// question marks inside string literals and quoted identifiers are left as is. The amount of placeholders is returned
+func replacePlaceholders(dialect, query string, fn func(n int) (string, error)) (string, int, error) {
+	sb := strings.Builder{}
+	sb.Grow(len(query))
+
+	var quote byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[' && dialect == DialectSQLServer:
+			quote = ']'
+		case c == '?':
+			s, err := fn(n)
+			if err != nil {
+				return "", 0, err
+			}
+			sb.WriteString(s)
+			n++
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String(), n, nil
+}
@@ -0,0 +1,55 @@
+package buildsqlx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	msg  string
+	args []interface{}
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) {
+	l.msg, l.args = msg, args
+}
+
+func TestDB_ToDebugSQL(t *testing.T) {
+	d := newDB(&Connection{driver: "postgres"}).Table("users").
+		Where("name", OpEQ, "O'Brien").
+		AndWhere("created_at", OpGT, time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)).
+		AndWhere("active", OpEQ, true)
+	query, err := d.ToDebugSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE "users"."name" = 'O''Brien'`+
+		` AND "users"."created_at" > '2022-01-02 03:04:05' AND "users"."active" = TRUE`, query)
+
+	// the query is left intact
+	_, values := d.Query()
+	assert.Len(t, values, 3)
+
+	query, err = newDB(conn).Table("users").WhereRaw("note <> '?' AND `a?` = ? AND deleted_at IS ?", 1, nil).ToDebugSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE note <> '?' AND `a?` = 1 AND deleted_at IS NULL", query)
+
+	_, err = newDB(conn).Table("users").WhereRaw("id = ? OR id = ?", 1).ToDebugSQL()
+	assert.ErrorIs(t, err, ErrBindingsMismatch)
+}
+
+func TestDB_DumpTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := newDB(conn).Table("users").Where("id", OpEQ, 1).DumpTo(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`id` = 1\n", buf.String())
+
+	l := &testLogger{}
+	newDB(conn).Table("users").Where("bin", OpEQ, []byte("ab")).LogTo(l)
+	assert.Equal(t, "buildsqlx query", l.msg)
+	assert.Equal(t, []interface{}{"sql", "SELECT * FROM `users` WHERE `users`.`bin` = X'6162'"}, l.args)
+
+	newDB(conn).LogTo(l)
+	assert.Equal(t, []interface{}{"error", ErrNoTable}, l.args)
+}
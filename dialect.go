@@ -0,0 +1,54 @@
+package buildsqlx
+
+import "strings"
+
+// SQL dialects the statements are rendered for, the driver name passed to NewConnection is mapped to one of them
+const (
+	DialectMySQL     = "mysql"
+	DialectMariaDB   = "mariadb"
+	DialectPostgres  = "postgres"
+	DialectSQLite    = "sqlite3"
+	DialectSQLServer = "sqlserver"
+)
+
+// dialectOf maps database/sql driver name to the dialect, MySQL is the default one
+func dialectOf(driverName string) string {
+	switch strings.ToLower(driverName) {
+	case "postgres", "postgresql", "pgx":
+		return DialectPostgres
+	case "sqlite", "sqlite3":
+		return DialectSQLite
+	case "sqlserver", "mssql":
+		return DialectSQLServer
+	case "mariadb":
+		return DialectMariaDB
+	default:
+		return DialectMySQL
+	}
+}
+
+// isMySQL reports whether the dialect is of MySQL family
+func isMySQL(dialect string) bool {
+	return dialect == DialectMySQL || dialect == DialectMariaDB
+}
+
+// quoteIdent quotes the identifier with the quotes of the dialect doubling the ones inside it,
+// each part of dotted name is quoted on its own and * is left as is, ex.: users.* -> `users`.*
+func quoteIdent(dialect, ident string) string {
+	open, closing := "`", "`"
+	switch dialect {
+	case DialectPostgres, DialectSQLite:
+		open, closing = `"`, `"`
+	case DialectSQLServer:
+		open, closing = "[", "]"
+	}
+
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = open + strings.ReplaceAll(part, closing, closing+closing) + closing
+	}
+	return strings.Join(parts, ".")
+}
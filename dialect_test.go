@@ -0,0 +1,71 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		dialect, ident, quoted string
+	}{
+		{DialectMySQL, "users", "`users`"},
+		{DialectMySQL, "us`ers", "`us``ers`"},
+		{DialectMariaDB, "db.users", "`db`.`users`"},
+		{DialectPostgres, `us"ers`, `"us""ers"`},
+		{DialectSQLite, "users.*", `"users".*`},
+		{DialectSQLServer, "us]ers", "[us]]ers]"},
+		{DialectSQLServer, "*", "*"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.quoted, quoteIdent(tt.dialect, tt.ident))
+	}
+}
+
+func TestDB_SelectQuoting(t *testing.T) {
+	query, _ := newDB(conn).Table("users").Select("password", "posts.*", "name AS author", "id`; DROP TABLE users; --").Query()
+	assert.Equal(t, "SELECT `password`, `posts`.*, `name` AS `author`, `id``; DROP TABLE users; --` FROM `users`", query)
+
+	query, _ = newDB(conn).Table("users").SelectRaw("COUNT(DISTINCT name) AS names").Query()
+	assert.Equal(t, "SELECT COUNT(DISTINCT name) AS names FROM `users`", query)
+
+	query, _ = newDB(&Connection{driver: "postgres"}).Table("users").Select("name").GroupBy("org, name").Max("points")
+	assert.Equal(t, `SELECT MAX("points") FROM "users" GROUP BY "org", "name"`, query)
+}
+
+func TestDB_AllowColumns(t *testing.T) {
+	query, values, err := newDB(conn).Table("users").AllowColumns("id", "name").
+		Select("id", "name").Where("name", OpEQ, "foo").OrderBy("id", "desc").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `name` FROM `users` WHERE `users`.`name` = ? ORDER BY `users`.`id` DESC", query)
+	assert.Equal(t, []interface{}{"foo"}, values)
+
+	_, _, err = newDB(conn).Table("users").AllowColumns("id").Select("password").ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+
+	_, _, err = newDB(conn).Table("users").AllowColumns("id").Where("password", OpEQ, "x").ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+
+	_, _, err = newDB(conn).Table("users").AllowColumns("id").OrderBy("password", "ASC").ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+}
+
+func TestDB_OrderByDirection(t *testing.T) {
+	query, _, err := newDB(conn).Table("users").OrderBy("id", "").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` ORDER BY `users`.`id` ASC", query)
+
+	_, _, err = newDB(conn).Table("users").OrderBy("id", "DESC; DROP TABLE users").ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidDirection)
+}
+
+func TestDB_JoinQuoting(t *testing.T) {
+	query, _, err := newDB(&Connection{driver: "sqlserver"}).Table("users").
+		LeftJoin("posts AS p", "p.user_id", " = ", "users.id").ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM [users] LEFT JOIN [posts] AS [p] ON [p].[user_id] = [users].[id]`, query)
+
+	_, _, err = newDB(conn).Table("users").InnerJoin("posts", "posts.user_id", "= 1 OR 1 =", "users.id").ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidOp)
+}
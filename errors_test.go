@@ -0,0 +1,76 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_NoTable(t *testing.T) {
+	d := newDB(conn)
+	query, values := d.Query()
+	assert.Empty(t, query)
+	assert.Nil(t, values)
+	assert.ErrorIs(t, d.Err(), ErrNoTable)
+
+	d = newDB(conn)
+	d.Insert(map[string]interface{}{"foo": 1})
+	assert.ErrorIs(t, d.Err(), ErrNoTable)
+
+	d = newDB(conn)
+	d.Delete()
+	assert.ErrorIs(t, d.Err(), ErrNoTable)
+
+	d = newDB(conn)
+	d.Replace(map[string]interface{}{"id": 1}, "id")
+	assert.ErrorIs(t, d.Err(), ErrNoTable)
+
+	_, _, err := newDB(conn).ToSQL()
+	assert.ErrorIs(t, err, ErrNoTable)
+
+	_, _, err = newDB(conn).InsertBatch([]map[string]interface{}{{"foo": 1}})
+	assert.ErrorIs(t, err, ErrNoTable)
+}
+
+func TestDB_InvalidOp(t *testing.T) {
+	d := newDB(conn).Table("users").Where("id", Op(100), 1)
+	_, _, err := d.ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidOp)
+
+	_, err = d.Paginate(1, 10)
+	assert.ErrorIs(t, err, ErrInvalidOp)
+
+	// errors survive the where clause being regrouped
+	d = newDB(conn).Table("users").Where("id", Op(100), 1)
+	_, err = d.SplitWhereIn("id", []interface{}{1, 2}, SplitOptions{})
+	assert.ErrorIs(t, err, ErrInvalidOp)
+
+	// reset clears the errors
+	_, _, err = d.Table("users").Where("id", OpEQ, 1).ToSQL()
+	assert.NoError(t, err)
+}
+
+func TestDB_ToSQL(t *testing.T) {
+	query, values, err := newDB(conn).Table("users").Where("id", OpEQ, 1).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`id` = ?", query)
+	assert.Equal(t, []interface{}{1}, values)
+}
+
+func TestDB_ReturningError(t *testing.T) {
+	_, _, err := newDB(&Connection{driver: "sqlserver"}).Table("users").Returning("id").
+		InsertIgnoreBatch([]map[string]interface{}{{"id": 1}})
+	assert.ErrorIs(t, err, ErrIgnoreUnsupported)
+
+	_, _, err = newDB(conn).Table("users").Returning("id").InsertBatch([]map[string]interface{}{{"id": 1}})
+	assert.ErrorIs(t, err, ErrReturningUnsupported)
+}
+
+func TestTable_NoColumn(t *testing.T) {
+	_, err := newDB(conn).CreateTable("users", func(table *Table) error {
+		table.NotNull().Default(1).Index("idx")
+		table.Increments("id")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNoColumn)
+}
@@ -0,0 +1,106 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateColumn = errors.New("sql: column is assigned more than once")
+
+// Expr is an sql expression with its param bindings, when used as an inserted or updated value
+// it's rendered as is instead of a placeholder
+type Expr struct {
+	col  string
+	sql  string
+	args []interface{}
+	// alias of the selected column
+	alias string
+}
+
+// Raw creates raw sql expression, ex.: Update(map[string]interface{}{"total": Raw("price * ?", 2)})
+func Raw(sql string, args ...interface{}) Expr {
+	return Expr{sql: sql, args: args}
+}
+
+// Col creates a reference to the column, ex.: Update(map[string]interface{}{"prev_status": Col("status")})
+func Col(name string) Expr {
+	return Expr{col: name}
+}
+
+// Add adds n to the column reference, ex.: Col("views").Add(1) renders `views` + ?
+func (e Expr) Add(n interface{}) Expr {
+	return e.arith(" + ", n)
+}
+
+// Sub subtracts n from the column reference, ex.: Col("balance").Sub(10) renders `balance` - ?
+func (e Expr) Sub(n interface{}) Expr {
+	return e.arith(" - ", n)
+}
+
+func (e Expr) arith(op string, n interface{}) Expr {
+	args := make([]interface{}, 0, len(e.args)+1)
+	args = append(args, e.args...)
+	return Expr{col: e.col, sql: e.sql + op + "?", args: append(args, n), alias: e.alias}
+}
+
+// bind returns the binding the value is rendered with and its args
+func (b *sqlBuilder) bind(v interface{}) (string, []interface{}) {
+	e, ok := v.(Expr)
+	if !ok {
+		return "?", []interface{}{v}
+	}
+
+	if e.col != "" {
+		return b.Quote(e.col) + e.sql, e.args
+	}
+	return e.sql, e.args
+}
+
+// Increment adds n to the column of the rows matched, extra columns are updated in the same stmt
+func (r *DB) Increment(col string, n interface{}, extra ...map[string]interface{}) (query string, values []interface{}) {
+	return r.IncrementEach(map[string]interface{}{col: n}, extra...)
+}
+
+// Decrement subtracts n from the column of the rows matched, extra columns are updated in the same stmt
+func (r *DB) Decrement(col string, n interface{}, extra ...map[string]interface{}) (query string, values []interface{}) {
+	return r.updateWithExtra(Pairs{{Column: col, Value: Col(col).Sub(n)}}, extra)
+}
+
+// IncrementEach adds the amounts to their columns of the rows matched, extra columns are updated in the same stmt
+func (r *DB) IncrementEach(amounts map[string]interface{}, extra ...map[string]interface{}) (query string, values []interface{}) {
+	row := make(Pairs, 0, len(amounts))
+	for _, col := range sortedKeys(amounts) {
+		row = append(row, Pair{Column: col, Value: Col(col).Add(amounts[col])})
+	}
+
+	return r.updateWithExtra(row, extra)
+}
+
+// updateWithExtra updates the row along with extra columns, assigning a column of the row again is recorded
+// as ErrDuplicateColumn, as some dialects reject it while MySQL applies both assignments
+func (r *DB) updateWithExtra(row Pairs, extra []map[string]interface{}) (query string, values []interface{}) {
+	for _, p := range extraPairs(extra) {
+		if _, ok := row.get(p.Column); ok {
+			r.Builder.setErr(fmt.Errorf("%w: %q", ErrDuplicateColumn, p.Column))
+			return
+		}
+		row = append(row, p)
+	}
+
+	return r.UpdatePairs(row)
+}
+
+// extraPairs merges extra columns maps to the pairs sorted by column
+func extraPairs(extra []map[string]interface{}) Pairs {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]interface{})
+	for _, m := range extra {
+		for col, v := range m {
+			merged[col] = v
+		}
+	}
+	return pairsOf(merged)
+}
@@ -0,0 +1,51 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Increment(t *testing.T) {
+	query, values := newDB(conn).Table("posts").Where("id", OpEQ, 7).Increment("views", 1)
+	assert.Equal(t, "UPDATE `posts` SET `views` = `views` + ? WHERE `posts`.`id` = ?", query)
+	assert.Equal(t, []interface{}{1, 7}, values)
+
+	query, values = newDB(conn).Table("accounts").Where("id", OpEQ, 7).Decrement("balance", 10, map[string]interface{}{"updated_at": "2022-01-01"})
+	assert.Equal(t, "UPDATE `accounts` SET `balance` = `balance` - ?, `updated_at` = ? WHERE `accounts`.`id` = ?", query)
+	assert.Equal(t, []interface{}{10, "2022-01-01", 7}, values)
+
+	query, values = newDB(conn).Table("stats").IncrementEach(map[string]interface{}{"views": 1, "clicks": 2})
+	assert.Equal(t, "UPDATE `stats` SET `clicks` = `clicks` + ?, `views` = `views` + ?", query)
+	assert.Equal(t, []interface{}{2, 1}, values)
+}
+
+func TestDB_UpdateExpr(t *testing.T) {
+	query, values := newDB(conn).Table("orders").Where("id", OpEQ, 7).Update(map[string]interface{}{
+		"prev_status": Col("status"),
+		"status":      "paid",
+		"total":       Raw("price * ? + ?", 2, 5),
+	})
+	assert.Equal(t, "UPDATE `orders` SET `prev_status` = `status`, `status` = ?, `total` = price * ? + ? WHERE `orders`.`id` = ?", query)
+	assert.Equal(t, []interface{}{"paid", 2, 5, 7}, values)
+
+	query, values = newDB(conn).Table("orders").Insert(map[string]interface{}{"id": 1, "created_at": Raw("NOW()")})
+	assert.Equal(t, "INSERT INTO `orders` (`created_at`, `id`) VALUES (NOW(), ?)", query)
+	assert.Equal(t, []interface{}{1}, values)
+}
+
+func TestDB_IncrementDuplicateColumn(t *testing.T) {
+	db := newDB(conn).Table("posts")
+	query, values := db.Increment("views", 1, map[string]interface{}{"views": 5})
+	assert.Empty(t, query)
+	assert.Empty(t, values)
+	assert.ErrorIs(t, db.Err(), ErrDuplicateColumn)
+
+	db = newDB(conn).Table("posts")
+	db.Decrement("stock", 1, map[string]interface{}{"stock": 0})
+	assert.ErrorIs(t, db.Err(), ErrDuplicateColumn)
+
+	db = newDB(conn).Table("stats")
+	db.IncrementEach(map[string]interface{}{"views": 1, "clicks": 2}, map[string]interface{}{"clicks": 0})
+	assert.ErrorIs(t, db.Err(), ErrDuplicateColumn)
+}
@@ -1,13 +1,25 @@
 package buildsqlx
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// insert statement verbs
 const (
-	// Errors
-	errTableCallBeforeOp = "sql: there was no Table() call with table name set"
+	insertInto         = "INSERT INTO"
+	insertIgnoreInto   = "INSERT IGNORE INTO"
+	insertOrIgnoreInto = "INSERT OR IGNORE INTO"
+)
+
+// Errors returned by the statements builders
+var (
+	ErrNoTable           = errors.New("sql: there was no Table() call with table name set")
+	ErrEmptyBatch        = errors.New("sql: there are no rows in the batch")
+	ErrUnknownColumn     = errors.New("sql: unknown column")
+	ErrClauseUnsupported = errors.New("sql: clause is not supported by the dialect")
 )
 
 // buildSelect constructs a query for select statement
@@ -15,27 +27,27 @@ func (r *builder) buildSelect() string {
 
 	// SELECT
 	r.WriteString("SELECT").Pad()
+	r.composeHints()
+	if r.distinct {
+		r.WriteString("DISTINCT").Pad()
+	}
 
 	// field
-	l := len(r.columns)
 	for k, col := range r.columns {
-		if col == "*" || strings.HasSuffix(col, ".*") {
-			r.WriteString(col)
-		} else {
-			// ``/()/AS/as
-			if strings.Contains(col, "`") || strings.Contains(col, "(") || strings.Contains(col, "AS") || strings.Contains(col, "as") {
-				r.WriteString(col)
-			} else {
-				r.Ident(col)
-			}
-		}
-		if k < l-1 {
+		if k > 0 {
 			r.Comma()
 		}
+
+		binding, _ := r.bind(col)
+		r.WriteString(binding)
+		if col.alias != "" {
+			r.Pad().WriteString("AS").Pad().Ident(col.alias)
+		}
 	}
 
 	// from
-	r.Pad().WriteString("FROM").Pad().Ident(r.table).Pad()
+	r.Pad().WriteString("FROM").Pad().Ident(r.table)
+	r.composeTableHints()
 
 	// Clauses
 	r.buildClauses()
@@ -45,18 +57,15 @@ func (r *builder) buildSelect() string {
 
 // builds query string clauses
 func (r *builder) buildClauses() {
-	for _, j := range r.join {
-		r.WriteString(j)
-	}
+	r.composeJoins()
 
 	// build where clause
 	if r.where.Len() > 0 {
 		r.WriteString(r.where.String())
 	}
 
-	if r.groupBy != "" {
-		r.Pad().WriteString("GROUP BY").Pad()
-		r.Ident(r.groupBy)
+	if len(r.groupBy) > 0 {
+		r.Pad().WriteString("GROUP BY").Pad().idents(r.groupBy)
 	}
 
 	if r.having.Len() > 0 {
@@ -65,62 +74,44 @@ func (r *builder) buildClauses() {
 	}
 
 	r.composeOrderBy()
-
-	if r.limit > 0 {
-		r.Pad().WriteString("LIMIT").Pad()
-		if r.offset > 0 {
-			r.WriteString(strconv.FormatInt(r.offset, 10)).Comma()
-		}
-		r.WriteString(strconv.FormatInt(r.limit, 10))
-	}
-
-	if r.lockForUpdate != nil {
-		r.Pad().WriteString(*r.lockForUpdate)
-	}
+	r.composeLimit()
+	r.composeLock()
 }
 
-// builds query string clauses
-func buildClauses(r *builder) string {
-
-	b := deepClone(r)
-	b.sb.Reset()
-
-	for _, j := range b.join {
-		b.WriteString(j)
-	}
-
-	// build where clause
-	if b.where.Len() > 0 {
-		b.WriteString(b.where.String())
-	}
-
-	if b.groupBy != "" {
-		b.Pad().WriteString("GROUP BY").Pad()
-		b.Ident(b.groupBy)
+// composeLimit writes LIMIT and OFFSET clauses, SQL Server gets OFFSET ... FETCH NEXT ... which needs ORDER BY,
+// so the rows are left unordered with ORDER BY (SELECT NULL) unless the query orders them
+func (r *builder) composeLimit() {
+	if r.limit <= 0 {
+		return
 	}
 
-	if b.having.Len() > 0 {
-		b.Pad().WriteString("HAVING").Pad()
-		b.WriteString(b.having.String())
+	if r.dialect == DialectSQLServer {
+		if len(r.orderBy) == 0 && r.orderByRaw == nil {
+			r.Pad().WriteString("ORDER BY (SELECT NULL)")
+		}
+		r.Pad().WriteString("OFFSET").Pad().WriteString(strconv.FormatInt(r.offset, 10)).Pad().WriteString("ROWS").
+			Pad().WriteString("FETCH NEXT").Pad().WriteString(strconv.FormatInt(r.limit, 10)).Pad().WriteString("ROWS ONLY")
+		return
 	}
 
-	b.composeOrderBy()
-
-	if b.limit > 0 {
-		b.Pad().WriteString("LIMIT").Pad()
-		b.WriteString(strconv.FormatInt(b.limit, 10))
+	r.Pad().WriteString("LIMIT").Pad().WriteString(strconv.FormatInt(r.limit, 10))
+	if r.offset > 0 {
+		r.Pad().WriteString("OFFSET").Pad().WriteString(strconv.FormatInt(r.offset, 10))
 	}
+}
 
-	if b.offset > 0 {
-		b.Comma()
-		b.WriteString(strconv.FormatInt(b.offset, 10))
-	}
+// builds query string clauses on a copy of the builder, leaving r untouched
+func buildClauses(r *builder) string {
+	b := r.fork()
+	b.buildClauses()
+	return b.String()
+}
 
-	if b.lockForUpdate != nil {
-		b.WriteString(*b.lockForUpdate)
+// composeJoins writes JOIN clauses
+func (r *builder) composeJoins() {
+	for _, j := range r.join {
+		r.Pad().WriteString(j.kind).WriteString(" JOIN ").WriteString(j.table).WriteString(" ON ").WriteString(j.on)
 	}
-
-	return b.String()
 }
 
 // composers ORDER BY clause string for particular query stmt
@@ -130,9 +121,9 @@ func (r *builder) composeOrderBy() {
 		for _, d := range r.orderBy {
 			if fist {
 				fist = false
-				r.Pad().WriteString("ORDER BY").Pad().IdentPoint(r.table).Ident(d.Column).Pad().WriteString(d.Direction)
+				r.Pad().WriteString("ORDER BY").Pad().Column(r.table, d.Column).Pad().WriteString(d.Direction)
 			} else {
-				r.Pad().Comma().IdentPoint(r.table).Ident(d.Column).Pad().WriteString(d.Direction)
+				r.Comma().Column(r.table, d.Column).Pad().WriteString(d.Direction)
 			}
 		}
 		return
@@ -141,16 +132,26 @@ func (r *builder) composeOrderBy() {
 	}
 }
 
-// Insert inserts one row with param bindings
+// Insert inserts one row with param bindings, columns are rendered in sorted order
 func (r *DB) Insert(data map[string]interface{}) (query string, values []interface{}) {
+	return r.InsertPairs(pairsOf(data))
+}
+
+// InsertPairs inserts one row with param bindings keeping the column order of row
+func (r *DB) InsertPairs(row Pairs) (query string, values []interface{}) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+		builder.setErr(ErrNoTable)
+		return
 	}
 
-	columns, values, bindings := prepareBindings(data)
+	columns, values, bindings, err := builder.prepareBindings(row)
+	if err != nil {
+		builder.setErr(err)
+		return "", nil
+	}
 
-	builder.WriteString("INSERT INTO").
+	builder.WriteString(insertInto).
 		Pad().Ident(builder.table).Pad().
 		Nested(func(s *sqlBuilder) {
 			l := len(columns)
@@ -160,108 +161,193 @@ func (r *DB) Insert(data map[string]interface{}) (query string, values []interfa
 					s.Comma()
 				}
 			}
-		}).
-		Pad().WriteString("VALUES").
+		})
+	builder.composeOutput(outputInserted)
+	builder.Pad().WriteString("VALUES").Pad().
 		Nested(func(s *sqlBuilder) {
 			s.WriteString(strings.Join(bindings, `, `))
 		})
+	builder.composeReturning("INSERT")
 
 	query = builder.String()
 
 	return
 }
 
-// prepareBindings prepares slices to split in favor of INSERT sql statement
-func prepareBindings(data map[string]interface{}) (columns []string, values []interface{}, bindings []string) {
-	i := 1
-	for column, value := range data {
-		columns = append(columns, column)
-		values = append(values, value)
-		bindings = append(bindings, "?")
-		i++
+// prepareBindings prepares slices to split in favor of INSERT sql statement,
+// Expr values are bound as they are
+func (r *builder) prepareBindings(row Pairs) (columns []string, values []interface{}, bindings []string, err error) {
+	for _, p := range row {
+		if e, ok := p.Value.(pairsErr); ok {
+			return nil, nil, nil, e.err
+		}
+		if p.Column == "" {
+			return nil, nil, nil, ErrEmptyColumn
+		}
+
+		binding, args := r.bind(p.Value)
+		columns = append(columns, p.Column)
+		values = append(values, args...)
+		bindings = append(bindings, binding)
 	}
 
 	return
 }
 
-// InsertBatch inserts multiple rows based on transaction
-func (r *DB) InsertBatch(data []map[string]interface{}) (query string, values [][]interface{}) {
+// InsertBatch inserts multiple rows with a single INSERT ... VALUES (...), (...) statement.
+// Columns are the union of all rows keys in sorted order unless stated explicitly,
+// in which case rows with any other column are rejected.
+// Values missing in a row are filled with DEFAULT (NULL on SQLite)
+func (r *DB) InsertBatch(data []map[string]interface{}, columns ...string) (query string, values []interface{}, err error) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+		return "", nil, ErrNoTable
 	}
 
-	columns, values, bindings := prepareInsertBatch(data)
+	columns, err = prepareInsertBatch(data, columns)
+	if err != nil {
+		return "", nil, err
+	}
 
-	builder.WriteString("INSERT INTO").
-		Pad().Ident(builder.table).Pad().
-		Nested(func(s *sqlBuilder) {
-			l := len(columns)
-			for k, col := range columns {
-				s.Ident(col)
-				if k < l-1 {
-					s.Comma()
+	values = builder.writeInsertBatch(insertInto, columns, data)
+	builder.composeReturning("INSERT")
+	return r.built(values)
+}
+
+// prepareInsertBatch collects the columns of the batch or checks rows against the ones stated
+func prepareInsertBatch(data []map[string]interface{}, columns []string) ([]string, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	if len(columns) > 0 {
+		known := make(map[string]struct{}, len(columns))
+		for _, col := range columns {
+			known[col] = struct{}{}
+		}
+
+		for k, row := range data {
+			for col := range row {
+				if _, ok := known[col]; !ok {
+					return nil, fmt.Errorf("%w: %q in row %d", ErrUnknownColumn, col, k)
 				}
 			}
-		}).
-		Pad().WriteString("VALUES").
+		}
+
+		return columns, nil
+	}
+
+	union := make(map[string]struct{})
+	for _, row := range data {
+		for col := range row {
+			union[col] = struct{}{}
+		}
+	}
+
+	return sortedKeys(union), nil
+}
+
+// writeInsertBatch writes multi-row INSERT statement starting with verb returning its flattened values
+func (r *builder) writeInsertBatch(verb string, columns []string, data []map[string]interface{}) (values []interface{}) {
+	missing := "DEFAULT"
+	if r.dialect == DialectSQLite {
+		missing = "NULL"
+	}
+
+	r.WriteString(verb).
+		Pad().Ident(r.table).Pad().
 		Nested(func(s *sqlBuilder) {
-			s.WriteString(strings.Join(bindings, `, `))
+			s.idents(columns)
 		})
+	r.composeOutput(outputInserted)
+	r.Pad().WriteString("VALUES").Pad()
 
-	query = builder.String()
-
-	return
+	return r.writeRows(columns, data, missing)
 }
 
-// prepareInsertBatch prepares slices to split in favor of INSERT sql statement
-func prepareInsertBatch(data []map[string]interface{}) (columns []string, values [][]interface{}, bindings []string) {
-	values = make([][]interface{}, len(data))
-	colToIdx := make(map[string]int)
-
-	i := 0
-	for k, v := range data {
-		values[k] = make([]interface{}, len(v))
-
-		for column, value := range v {
-			if k == 0 {
-				columns = append(columns, column)
-				bindings = append(bindings, "?")
-				// todo: don't know yet how to match them explicitly (it is bad idea, but it works well now)
-				colToIdx[column] = i
-				i++
+// writeRows writes rows value lists, missing values are replaced with missing keyword
+func (r *builder) writeRows(columns []string, data []map[string]interface{}, missing string) (values []interface{}) {
+	values = make([]interface{}, 0, len(data)*len(columns))
+	for i, row := range data {
+		if i > 0 {
+			r.Comma()
+		}
+
+		r.WriteByte('(')
+		for k, col := range columns {
+			if k > 0 {
+				r.Comma()
 			}
 
-			values[k][colToIdx[column]] = value
+			if v, ok := row[col]; ok {
+				binding, args := r.bind(v)
+				r.WriteString(binding)
+				values = append(values, args...)
+			} else {
+				r.WriteString(missing)
+			}
 		}
+		r.WriteByte(')')
 	}
 
 	return
 }
 
 // Update builds an UPDATE sql stmt with corresponding where/from clauses if stated
-// returning affected rows
+// returning affected rows, columns are rendered in sorted order.
+// Values may be expressions or other columns, ex.: Update(map[string]interface{}{"views": Col("views").Add(1)})
 func (r *DB) Update(data map[string]interface{}) (query string, values []interface{}) {
+	return r.UpdatePairs(pairsOf(data))
+}
+
+// UpdatePairs builds an UPDATE sql stmt keeping the column order of row
+func (r *DB) UpdatePairs(row Pairs) (query string, values []interface{}) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+		builder.setErr(ErrNoTable)
+		return
 	}
 
-	columns, values, bindings := prepareBindings(data)
+	r.applyScopes()
 
-	builder.WriteString("UPDATE").
-		Pad().Ident(builder.table).Pad().
-		WriteString("SET")
+	columns, values, bindings, err := builder.prepareBindings(row)
+	if err != nil {
+		builder.setErr(err)
+		return "", nil
+	}
+	multi := builder.isMultiTable()
+
+	builder.WriteString("UPDATE").Pad().Ident(builder.table)
+	if multi && isMySQL(builder.dialect) {
+		// MySQL joins the tables right away: UPDATE a JOIN b ON ... SET ...
+		builder.composeFrom()
+		builder.composeJoins()
+	}
+	builder.Pad().WriteString("SET").Pad()
 
-	l := len(columns)
 	for k, col := range columns {
-		builder.Pad().Ident(col).WriteOp(OpEQ).WriteString(bindings[k])
-		if k < l-1 {
+		if k > 0 {
 			builder.Comma()
 		}
+		builder.Ident(col).WriteOp(OpEQ).WriteString(bindings[k])
+	}
+
+	builder.composeOutput(outputInserted)
+	if multi {
+		switch builder.dialect {
+		case DialectSQLServer:
+			// UPDATE a SET ... FROM a JOIN b ON ...
+			builder.Pad().WriteString("FROM").Pad().Ident(builder.table)
+			builder.composeFrom()
+			builder.composeJoins()
+		case DialectPostgres, DialectSQLite:
+			// UPDATE a SET ... FROM b WHERE <join condition>
+			builder.composeUsing("FROM")
+		}
 	}
 
-	builder.buildClauses()
+	builder.composeWriteClauses("UPDATE")
+	builder.composeReturning("UPDATE")
 
 	query += builder.String()
 	values = append(values, r.Builder.where.args...)
@@ -269,181 +355,145 @@ func (r *DB) Update(data map[string]interface{}) (query string, values []interfa
 	return
 }
 
-func (r *DB) UpdateBatch(where map[string][]int, update map[string][]interface{}) (query string, values []interface{}) {
+// Delete builds a DELETE stmt with corresponding where clause if stated
+// returning affected rows
+func (r *DB) Delete() (query string, values []interface{}) {
 	builder := r.Builder
 	if builder.table == "" {
-		panic(errTableCallBeforeOp)
-	}
-
-	if len(where) == 0 || len(update) == 0 {
+		builder.setErr(ErrNoTable)
 		return
 	}
 
-	builder.WriteString("UPDATE").
-		Pad().Ident(builder.table).Pad().
-		WriteString("SET").Pad()
-
-	// 所有的条件字段数组
-	var whereKeys []string
-	for k := range where {
-		whereKeys = append(whereKeys, k)
+	r.applyScopes()
+
+	if !builder.isMultiTable() {
+		builder.WriteString("DELETE FROM").Pad().Ident(builder.table)
+		builder.composeOutput(outputDeleted)
+	} else {
+		switch builder.dialect {
+		case DialectPostgres:
+			// DELETE FROM a USING b WHERE <join condition>
+			builder.WriteString("DELETE FROM").Pad().Ident(builder.table)
+			builder.composeUsing("USING")
+		case DialectSQLite:
+			builder.setErr(fmt.Errorf("%w: DELETE with joined tables on %s", ErrClauseUnsupported, builder.dialect))
+			builder.WriteString("DELETE FROM").Pad().Ident(builder.table)
+		default:
+			// DELETE a FROM a JOIN b ON ...
+			builder.WriteString("DELETE").Pad().Ident(builder.table)
+			builder.composeOutput(outputDeleted)
+			builder.Pad().WriteString("FROM").Pad().Ident(builder.table)
+			builder.composeFrom()
+			builder.composeJoins()
+		}
 	}
-	// 第一个 where 条件所有的值
-	firstWhere := where[whereKeys[0]]
 
-	// 所有需要更新的字段数组
-	var needUpdateFieldsKeys []string
-	for k := range update {
-		needUpdateFieldsKeys = append(needUpdateFieldsKeys, k)
-	}
+	builder.composeWriteClauses("DELETE")
+	builder.composeReturning("DELETE")
 
-	if len(firstWhere) != len(update[needUpdateFieldsKeys[0]]) {
-		// 更新的条件与更新的字段值数量不相等
-		return
-	}
+	query = builder.String()
+	values = r.Builder.where.args
 
-	type whereObj struct {
-		key   string
-		value int
-	}
+	return
+}
 
-	var s1 []whereObj
-	for k := range firstWhere {
-		for _, vv := range whereKeys {
-			// s1 = append(s1, fmt.Sprintf("`%s` = %v AND ", vv, where[vv][k]))
-			s1 = append(s1, whereObj{
-				key:   vv,
-				value: where[vv][k],
-			})
-		}
-	}
+// isMultiTable reports whether UPDATE or DELETE stmt refers other tables
+func (r *builder) isMultiTable() bool {
+	return r.from != "" || len(r.join) > 0
+}
 
-	// 按照 where 条件字段数量做切割
-	whereSize := len(whereKeys)
-	batches := make([][]whereObj, 0, (len(s1)+whereSize-1)/whereSize)
-	for whereSize < len(s1) {
-		s1, batches = s1[whereSize:], append(batches, s1[0:whereSize:whereSize])
-	}
-	batches = append(batches, s1)
-
-	// var whereArr []string
-	// for _, v := range batches {
-	// 	whereArr = append(whereArr, strings.TrimSuffix(strings.Join(v, " "), "AND "))
-	// }
-
-	// 拼接 sql 语句
-	for i, v := range needUpdateFieldsKeys {
-		// str := ""
-		// for kk, vv := range whereArr {
-		// 	str += fmt.Sprintf(" WHEN %v THEN %v ", vv, update[v][kk])
-		// }
-
-		if i < len(needUpdateFieldsKeys)-1 {
-
-			builder.Ident(v).WriteString(" = CASE ")
-
-			// 编辑case
-			for j, b := range batches {
-				// where条件
-				builder.WriteString(" WHEN ")
-				for k, w := range b {
-					if k < len(b)-1 {
-						builder.Ident(w.key).WriteString(" = ").Arg(w.value).WriteString(" AND ")
-					} else {
-						builder.Ident(w.key).WriteString(" = ").Arg(w.value)
-					}
-				}
+// composeFrom writes the table set by From as a comma separated one
+func (r *builder) composeFrom() {
+	if r.from != "" {
+		r.Comma().Ident(r.from)
+	}
+}
 
-				// 更新内容
-				builder.WriteString(" THEN ")
-				builder.Arg(update[v][j])
-			}
+// composeUsing writes the tables of UPDATE ... FROM or DELETE ... USING (PostgreSQL, SQLite),
+// moving the joins conditions to WHERE clause
+func (r *builder) composeUsing(keyword string) {
+	r.Pad().WriteString(keyword).Pad()
 
-			builder.WriteString(" ELSE ").Ident(v).WriteString(" END, ")
-
-			// builder.WriteString(fmt.Sprintf("`%s` = CASE %s ELSE `%s` END, ", v, str, v))
-		} else {
-			builder.Ident(v).WriteString(" = CASE ")
-
-			// 编辑case
-			for j, b := range batches {
-				// where条件
-				builder.WriteString(" WHEN ")
-				for k, w := range b {
-					if k < len(b)-1 {
-						builder.Ident(w.key).WriteString(" = ").Arg(w.value).WriteString(" AND ")
-					} else {
-						builder.Ident(w.key).WriteString(" = ").Arg(w.value)
-					}
-				}
+	tables := 0
+	if r.from != "" {
+		r.Ident(r.from)
+		tables++
+	}
 
-				// 更新内容
-				builder.WriteString(" THEN ")
-				builder.Arg(update[v][j])
-			}
+	cond := &sqlBuilder{dialect: r.dialect}
+	for _, j := range r.join {
+		if j.kind != joinInner {
+			r.setErr(fmt.Errorf("%w: %s JOIN in UPDATE/DELETE on %s", ErrClauseUnsupported, j.kind, r.dialect))
+		}
 
-			builder.WriteString(" ELSE ").Ident(v).WriteString(" END")
-			// builder.WriteString(fmt.Sprintf("`%s` = CASE %s ELSE `%s` END", v, str, v))
+		if tables > 0 {
+			r.Comma()
 		}
+		r.WriteString(j.table)
+		tables++
 
+		if cond.Len() > 0 {
+			cond.WriteString(and)
+		}
+		cond.WriteString(j.on)
 	}
 
-	query += builder.String()
-
-	values = append(values, builder.args...)
+	if cond.Len() == 0 {
+		return
+	}
 
-	return
+	w := r.where.derive()
+	w.WriteString(where).WriteString(cond.String())
+	if r.where.Len() > 0 {
+		w.WriteString(and).WriteByte('(').WriteString(strings.TrimPrefix(r.where.String(), where)).WriteByte(')')
+		w.args = append(w.args, r.where.args...)
+	}
+	r.where = w
 }
 
-// Delete builds a DELETE stmt with corresponding where clause if stated
-// returning affected rows
-func (r *DB) Delete() (query string, values []interface{}) {
-	builder := r.Builder
-	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+// composeWriteClauses writes WHERE, ORDER BY and LIMIT clauses of UPDATE or DELETE stmt,
+// the latter two are supported by MySQL and SQLite for single table stmt only
+func (r *builder) composeWriteClauses(verb string) {
+	if r.where.Len() > 0 {
+		r.WriteString(r.where.String())
 	}
 
-	builder.WriteString("DELETE FROM").
-		Pad().Ident(builder.table).Pad()
-
-	builder.buildClauses()
+	if len(r.orderBy) == 0 && r.orderByRaw == nil && r.limit == 0 {
+		return
+	}
 
-	query = builder.String()
-	values = r.Builder.where.args
+	if r.isMultiTable() || !(isMySQL(r.dialect) || r.dialect == DialectSQLite) {
+		r.setErr(fmt.Errorf("%w: ORDER BY/LIMIT in %s on %s", ErrClauseUnsupported, verb, r.dialect))
+		return
+	}
 
-	return
+	r.composeOrderBy()
+	if r.limit > 0 {
+		r.Pad().WriteString("LIMIT").Pad().WriteString(strconv.FormatInt(r.limit, 10))
+	}
 }
 
-// Replace inserts data if conflicting row hasn't been found, else it will update an existing one
+// Replace inserts data if conflicting row hasn't been found, else it will update an existing one,
+// conflict is a comma separated list of the unique key columns, see Upsert
 func (r *DB) Replace(data map[string]interface{}, conflict string) (query string, values []interface{}) {
-	builder := r.Builder
-	if builder.table == "" {
-		panic(errTableCallBeforeOp)
+	var conflictCols []string
+	for _, col := range strings.Split(conflict, ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			conflictCols = append(conflictCols, col)
+		}
 	}
 
-	columns, values, bindings := prepareBindings(data)
-
-	builder.WriteString("INSERT INTO").
-		Pad().Ident(builder.table).Pad().
-		Nested(func(s *sqlBuilder) {
-			s.WriteString(strings.Join(columns, `, `))
-		}).
-		Pad().WriteString("VALUES").
-		Nested(func(s *sqlBuilder) {
-			s.WriteString(strings.Join(bindings, `, `))
-		}).
-		WriteString("ON DUPLICATE KEY UPDATE").Pad()
-
-	l := len(columns)
-	for i, v := range columns {
-		builder.Ident(v).WriteOp(OpEQ).Pad().WriteString("excluded.").WriteString(v)
-		if i < l-1 {
-			builder.Comma()
+	updateCols := make([]string, 0, len(data))
+	for _, col := range sortedKeys(data) {
+		if !inStrings(conflictCols, col) {
+			updateCols = append(updateCols, col)
 		}
 	}
 
-	query = builder.String()
-
+	query, values, err := r.Upsert(data, conflictCols, updateCols)
+	if err != nil {
+		r.Builder.setErr(err)
+	}
 	return
 }
 
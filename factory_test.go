@@ -2,6 +2,8 @@ package buildsqlx
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDB_Insert(t *testing.T) {
@@ -12,12 +14,30 @@ func TestDB_Insert(t *testing.T) {
 
 func TestDB_InsertBatch(t *testing.T) {
 	// 	Insert
-	query, values := db.Table("table1").InsertBatch([]map[string]interface{}{
+	query, values, err := db.Table("table1").InsertBatch([]map[string]interface{}{
 		0: {"foo": "foo foo foo", "bar": "bar bar bar", "baz": 123},
 		1: {"foo": "foo foo foo foo", "bar": "bar bar bar bar", "baz": 1234},
-		2: {"foo": "foo foo foo foo foo", "bar": "bar bar bar bar bar", "baz": 12345},
+		2: {"foo": "foo foo foo foo foo", "bar": "bar bar bar bar bar"},
 	})
-	t.Logf("Insert query: %v, values:%+v", query, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table1` (`bar`, `baz`, `foo`) VALUES (?, ?, ?), (?, ?, ?), (?, DEFAULT, ?)", query)
+	assert.Equal(t, []interface{}{
+		"bar bar bar", 123, "foo foo foo",
+		"bar bar bar bar", 1234, "foo foo foo foo",
+		"bar bar bar bar bar", "foo foo foo foo foo",
+	}, values)
+
+	query, _, err = newDB(&Connection{driver: "sqlite3"}).Table("table1").InsertBatch([]map[string]interface{}{
+		{"foo": 1},
+		{"bar": 2},
+	}, "foo", "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "table1" ("foo", "bar") VALUES (?, NULL), (NULL, ?)`, query)
+
+	_, _, err = db.Table("table1").InsertBatch([]map[string]interface{}{{"foo": 1, "qux": 2}}, "foo", "bar")
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+	_, _, err = db.Table("table1").InsertBatch(nil)
+	assert.ErrorIs(t, err, ErrEmptyBatch)
 }
 func TestDB_Updates(t *testing.T) {
 	// 	Insert
@@ -31,16 +51,121 @@ func TestDB_Delete(t *testing.T) {
 }
 
 func TestDB_UpdateBatch(t *testing.T) {
-	// where
-	where := make(map[string][]int)
-	where["id"] = []int{1, 2, 3, 4}
-	// where["did"] = []int{1, 2, 3, 4}
-	// update
-	update := make(map[string][]interface{})
-	update["name"] = []interface{}{"a1", "a2", "a3", "a4"}
-	update["org"] = []interface{}{"b1", "b2", "b3", "b4"}
-
-	// 	update
-	query, values := db.Table("table1").UpdateBatch(where, update)
-	t.Logf("Update query: %v, values:%+v", query, values)
+	where := map[string][]int{"id": {1, 2}}
+	update := map[string][]interface{}{"name": {"a1", "a2"}, "org": {"b1", "b2"}}
+
+	query, values := newDB(conn).Table("table1").UpdateBatch(where, update)
+	assert.Equal(t, "UPDATE `table1` SET `name` = CASE WHEN `id` = ? THEN ? WHEN `id` = ? THEN ? ELSE `name` END, "+
+		"`org` = CASE WHEN `id` = ? THEN ? WHEN `id` = ? THEN ? ELSE `org` END WHERE `table1`.`id` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{1, "a1", 2, "a2", 1, "b1", 2, "b2", 1, 2}, values)
+
+	d := newDB(conn).Table("table1")
+	query, _ = d.UpdateBatch(where, map[string][]interface{}{"name": {"a1"}})
+	assert.Empty(t, query)
+	assert.ErrorIs(t, d.Err(), ErrBatchLengthMismatch)
+}
+
+func TestDB_InsertOrder(t *testing.T) {
+	data := map[string]interface{}{"foo": "foo foo foo", "bar": "bar bar bar", "baz": int64(123)}
+	for i := 0; i < 10; i++ {
+		query, values := newDB(conn).Table("table1").Insert(data)
+		assert.Equal(t, "INSERT INTO `table1` (`bar`, `baz`, `foo`) VALUES (?, ?, ?)", query)
+		assert.Equal(t, []interface{}{"bar bar bar", int64(123), "foo foo foo"}, values)
+	}
+
+	query, values := newDB(conn).Table("table1").InsertPairs(Cols("foo", "bar").Values("foo foo foo", "bar bar bar"))
+	assert.Equal(t, "INSERT INTO `table1` (`foo`, `bar`) VALUES (?, ?)", query)
+	assert.Equal(t, []interface{}{"foo foo foo", "bar bar bar"}, values)
+
+	db := newDB(conn).Table("table1")
+	query, values = db.InsertPairs(Cols("foo", "bar").Values(1))
+	assert.Empty(t, query)
+	assert.Empty(t, values)
+	assert.ErrorIs(t, db.Err(), ErrPairsMismatch)
+
+	db = newDB(conn).Table("table1")
+	db.UpdatePairs(Cols("foo", "bar").Values(1, 2, 3))
+	assert.ErrorIs(t, db.Err(), ErrPairsMismatch)
+
+	_, _, err := newDB(conn).Table("table1").UpdateRows([]string{"foo"}, []Pairs{Cols("foo", "bar").Values(1)}, BatchCase)
+	assert.ErrorIs(t, err, ErrPairsMismatch)
+
+	db = newDB(conn).Table("table1")
+	query, values = db.InsertPairs(append(Cols("foo", "bar").Values(1), Pair{"baz", 3}))
+	assert.Empty(t, query)
+	assert.Empty(t, values)
+	assert.ErrorIs(t, db.Err(), ErrPairsMismatch)
+	assert.ErrorIs(t, append(Pairs{{"baz", 3}}, Cols("foo").Values()...).Err(), ErrPairsMismatch)
+
+	db = newDB(conn).Table("table1")
+	db.UpdatePairs(Pairs{{"foo", 1}, {"", 2}})
+	assert.ErrorIs(t, db.Err(), ErrEmptyColumn)
+}
+
+func TestDB_UpdatePairs(t *testing.T) {
+	query, values := newDB(conn).Table("posts").Where("id", OpEQ, 3).UpdatePairs(Pairs{{"title", "awesome"}, {"body", "text"}})
+	assert.Equal(t, "UPDATE `posts` SET `title` = ?, `body` = ? WHERE `posts`.`id` = ?", query)
+	assert.Equal(t, []interface{}{"awesome", "text", 3}, values)
+}
+
+func TestDB_UpdateJoin(t *testing.T) {
+	build := func(driver string) *DB {
+		return newDB(&Connection{driver: driver}).Table("orders").
+			InnerJoin("users", "users.id", " = ", "orders.user_id").
+			Where("status", OpEQ, "new")
+	}
+	data := map[string]interface{}{"status": "cancelled"}
+
+	query, values := build("mysql").Update(data)
+	assert.Equal(t, "UPDATE `orders` INNER JOIN `users` ON `users`.`id` = `orders`.`user_id` SET `status` = ? WHERE `orders`.`status` = ?", query)
+	assert.Equal(t, []interface{}{"cancelled", "new"}, values)
+
+	query, values = build("postgres").Update(data)
+	assert.Equal(t, `UPDATE "orders" SET "status" = ? FROM "users" WHERE "users"."id" = "orders"."user_id" AND ("orders"."status" = ?)`, query)
+	assert.Equal(t, []interface{}{"cancelled", "new"}, values)
+
+	query, _ = build("sqlserver").Update(data)
+	assert.Equal(t, `UPDATE [orders] SET [status] = ? FROM [orders] INNER JOIN [users] ON [users].[id] = [orders].[user_id] WHERE [orders].[status] = ?`, query)
+
+	query, _ = newDB(&Connection{driver: "postgres"}).Table("employees").From("accounts").Update(data)
+	assert.Equal(t, `UPDATE "employees" SET "status" = ? FROM "accounts"`, query)
+
+	d := build("mysql").OrderBy("id", "ASC").Limit(10)
+	d.Update(data)
+	assert.ErrorIs(t, d.Err(), ErrClauseUnsupported)
+
+	d = newDB(&Connection{driver: "postgres"}).Table("orders").LeftJoin("users", "users.id", " = ", "orders.user_id")
+	d.Update(data)
+	assert.ErrorIs(t, d.Err(), ErrClauseUnsupported)
+}
+
+func TestDB_DeleteJoin(t *testing.T) {
+	build := func(driver string) *DB {
+		return newDB(&Connection{driver: driver}).Table("orders").
+			InnerJoin("users", "users.id", " = ", "orders.user_id").
+			Where("status", OpEQ, "new")
+	}
+
+	query, values := build("mysql").Delete()
+	assert.Equal(t, "DELETE `orders` FROM `orders` INNER JOIN `users` ON `users`.`id` = `orders`.`user_id` WHERE `orders`.`status` = ?", query)
+	assert.Equal(t, []interface{}{"new"}, values)
+
+	query, _ = build("postgres").Delete()
+	assert.Equal(t, `DELETE FROM "orders" USING "users" WHERE "users"."id" = "orders"."user_id" AND ("orders"."status" = ?)`, query)
+
+	d := build("sqlite3")
+	d.Delete()
+	assert.ErrorIs(t, d.Err(), ErrClauseUnsupported)
+}
+
+func TestDB_DeleteLimit(t *testing.T) {
+	d := newDB(conn).Table("sessions").Where("expired_at", OpLT, "2022-01-01").OrderBy("id", "ASC").Limit(1000)
+	query, values := d.Delete()
+	assert.Equal(t, "DELETE FROM `sessions` WHERE `sessions`.`expired_at` < ? ORDER BY `sessions`.`id` ASC LIMIT 1000", query)
+	assert.Equal(t, []interface{}{"2022-01-01"}, values)
+	assert.NoError(t, d.Err())
+
+	d = newDB(&Connection{driver: "postgres"}).Table("sessions").Limit(1000)
+	d.Delete()
+	assert.ErrorIs(t, d.Err(), ErrClauseUnsupported)
 }
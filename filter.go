@@ -0,0 +1,286 @@
+package buildsqlx
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidFilter is recorded for filter tags, keys and values WhereStruct and WhereMap can't apply
+var ErrInvalidFilter = errors.New("sql: invalid filter")
+
+// filterOps are the operators of filter tags
+var filterOps = map[string]Op{
+	"eq":         OpEQ,
+	"ne":         OpNEQ,
+	"gt":         OpGT,
+	"gte":        OpGTE,
+	"lt":         OpLT,
+	"lte":        OpLTE,
+	"in":         OpIn,
+	"notin":      OpNotIn,
+	"like":       OpLike,
+	"notlike":    OpNotLike,
+	"between":    OpBetween,
+	"notbetween": OpNotBetween,
+	"null":       OpIsNull,
+}
+
+// filter is the condition of a struct field or a map entry
+type filter struct {
+	col       string
+	op        Op
+	opSet     bool
+	omitEmpty bool
+	value     reflect.Value
+}
+
+// WhereStruct ANDs the conditions read from the fields of struct v to WHERE clause, the fields are tagged as
+//
+//	filter:"col,op=gte,omitempty"
+//
+// The column defaults to the snake cased field name, "-" skips the field, embedded structs are walked.
+// Operators are eq (default), ne, gt, gte, lt, lte, like, notlike, in, notin, between, notbetween
+// and null (IS NULL if the bool value is true, IS NOT NULL otherwise). Values are applied as follows:
+//   - nil pointers, slices, maps and interfaces and driver.Valuer returning nil are absent
+//   - zero values are absent with omitempty and compared otherwise
+//   - non-nil pointers are always compared even if they point to zero values, so use *T to filter by 0 or ""
+//   - slices but []byte are IN lists (or between bounds), empty ones are absent with omitempty
+//
+// Columns are checked against AllowColumns
+func (r *DB) WhereStruct(v interface{}) *DB {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return r
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		r.Builder.setErr(fmt.Errorf("%w: %T is not a struct", ErrInvalidFilter, v))
+		return r
+	}
+
+	filters, err := structFilters(rv, nil)
+	if err != nil {
+		r.Builder.setErr(err)
+		return r
+	}
+	return r.whereFilters(filters)
+}
+
+// WhereMap ANDs the conditions of the map to WHERE clause in the keys order, keys are columns
+// optionally followed by the options of WhereStruct tags, ex.:
+//
+//	db.Table("users").WhereMap(map[string]interface{}{"status": []string{"active", "new"}, "points,op=gte": 10})
+//
+// nil values are absent, the values are applied as WhereStruct fields values
+func (r *DB) WhereMap(m map[string]interface{}) *DB {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	filters := make([]filter, 0, len(m))
+	for _, key := range keys {
+		f, err := parseFilter(key, "")
+		if err != nil {
+			r.Builder.setErr(err)
+			return r
+		}
+		f.value = reflect.ValueOf(m[key])
+		filters = append(filters, f)
+	}
+	return r.whereFilters(filters)
+}
+
+// structFilters collects the filters of the struct fields
+func structFilters(v reflect.Value, filters []filter) ([]filter, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			var err error
+			if filters, err = structFilters(v.Field(i), filters); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("filter")
+		if tag == "-" {
+			continue
+		}
+		f, err := parseFilter(tag, snakeCase(field.Name))
+		if err != nil {
+			return nil, err
+		}
+		f.value = v.Field(i)
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// parseFilter parses the filter tag col,op=gte,omitempty, the column defaults to col
+func parseFilter(tag, col string) (filter, error) {
+	parts := strings.Split(tag, ",")
+	f := filter{col: strings.TrimSpace(parts[0])}
+	if f.col == "" {
+		f.col = col
+	}
+	if f.col == "" {
+		return f, fmt.Errorf("%w: no column in %q", ErrInvalidFilter, tag)
+	}
+
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "omitempty":
+			f.omitEmpty = true
+		case strings.HasPrefix(opt, "op="):
+			op, ok := filterOps[strings.ToLower(strings.TrimPrefix(opt, "op="))]
+			if !ok {
+				return f, fmt.Errorf("%w: unknown operator in %q", ErrInvalidFilter, tag)
+			}
+			f.op, f.opSet = op, true
+		default:
+			return f, fmt.Errorf("%w: unknown option %q in %q", ErrInvalidFilter, opt, tag)
+		}
+	}
+	return f, nil
+}
+
+// resolve unwraps the value of the filter reporting whether it's present
+func (f *filter) resolve() bool {
+	v := f.value
+	explicit := false
+	for v.IsValid() {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			if v.IsNil() {
+				return false
+			}
+		}
+		if valuer, ok := v.Interface().(driver.Valuer); ok {
+			// sql.Null* types are absent unless they are valid
+			if dv, err := valuer.Value(); err == nil && dv == nil {
+				return false
+			}
+			break
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+			break
+		}
+
+		explicit = explicit || v.Kind() == reflect.Ptr
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return false
+	}
+
+	if f.omitEmpty && !explicit {
+		if v.IsZero() || (f.isList(v) && v.Len() == 0) {
+			return false
+		}
+	}
+
+	f.value = v
+	return true
+}
+
+// isList reports whether v is a list of values rather than a single one
+func (f *filter) isList(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// write writes the condition of the filter
+func (f *filter) write(s *sqlBuilder, table string) {
+	v := f.value
+	list := f.isList(v)
+	op := f.op
+	if !f.opSet && list {
+		op = OpIn
+	}
+
+	switch op {
+	case OpIn, OpNotIn:
+		if !list {
+			s.setErr(fmt.Errorf("%w: %s of %q needs a list", ErrInvalidFilter, ops[op], f.col))
+			return
+		}
+		if v.Len() == 0 {
+			s.setErr(fmt.Errorf("%w: %q", ErrEmptyWhereInValues, f.col))
+			return
+		}
+
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).Interface()
+		}
+		s.Column(table, f.col).WriteOp(op).Nested(func(b *sqlBuilder) {
+			b.Args(values...)
+		})
+	case OpBetween, OpNotBetween:
+		if !list || v.Len() != 2 {
+			s.setErr(fmt.Errorf("%w: %s of %q needs 2 values", ErrInvalidFilter, ops[op], f.col))
+			return
+		}
+
+		s.Column(table, f.col).WriteOp(op).
+			Arg(v.Index(0).Interface()).Pad().
+			WriteString("AND").Pad().
+			Arg(v.Index(1).Interface())
+	case OpIsNull:
+		if v.Kind() != reflect.Bool {
+			s.setErr(fmt.Errorf("%w: null of %q needs a bool", ErrInvalidFilter, f.col))
+			return
+		}
+
+		if !v.Bool() {
+			op = OpNotNull
+		}
+		s.Column(table, f.col).WriteOp(op)
+	default:
+		if list {
+			s.setErr(fmt.Errorf("%w: %s of %q needs a single value", ErrInvalidFilter, ops[op], f.col))
+			return
+		}
+
+		s.Column(table, f.col).WriteOp(op).Arg(v.Interface())
+	}
+}
+
+// whereFilters ANDs the present filters to WHERE clause
+func (r *DB) whereFilters(filters []filter) *DB {
+	present := filters[:0]
+	for _, f := range filters {
+		if f.resolve() {
+			present = append(present, f)
+		}
+	}
+	if len(present) == 0 {
+		return r
+	}
+
+	r.Builder.andWhereGroup(func(s *sqlBuilder) {
+		for i := range present {
+			if i > 0 {
+				s.WriteString(and)
+			}
+			present[i].write(s, r.Builder.table)
+		}
+	})
+	return r
+}
@@ -0,0 +1,81 @@
+package buildsqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pagination struct {
+	Page int `filter:"-"`
+}
+
+type postFilter struct {
+	pagination
+	Status    []string       `filter:",omitempty"`
+	AuthorID  *int           `filter:"user_id"`
+	MinPoints int            `filter:"points,op=gte,omitempty"`
+	Title     string         `filter:"title,op=like,omitempty"`
+	Deleted   *bool          `filter:"deleted_at,op=null"`
+	Period    []string       `filter:"created_at,op=between,omitempty"`
+	Slug      sql.NullString `filter:",omitempty"`
+	Draft     bool
+}
+
+func TestDB_WhereStruct(t *testing.T) {
+	author, deleted := 0, false
+	query, values := newDB(conn).Table("posts").Where("tenant_id", OpEQ, 1).WhereStruct(&postFilter{
+		Status:   []string{"new", "hot"},
+		AuthorID: &author,
+		Title:    "%go%",
+		Deleted:  &deleted,
+		Period:   []string{"2024-01-01", "2024-02-01"},
+	}).Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE (`posts`.`tenant_id` = ?) AND `posts`.`status` IN (?, ?)"+
+		" AND `posts`.`user_id` = ? AND `posts`.`title` LIKE ? AND `posts`.`deleted_at` IS NOT NULL"+
+		" AND `posts`.`created_at` BETWEEN ? AND ? AND `posts`.`draft` = ?", query)
+	assert.Equal(t, []interface{}{1, "new", "hot", 0, "%go%", "2024-01-01", "2024-02-01", false}, values)
+
+	// nil pointers, invalid sql.Null* and empty values with omitempty are absent
+	query, values = newDB(conn).Table("posts").WhereStruct(postFilter{Status: []string{}, Draft: true}).Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`draft` = ?", query)
+	assert.Equal(t, []interface{}{true}, values)
+
+	query, values = newDB(conn).Table("posts").WhereStruct(postFilter{Slug: sql.NullString{String: "a", Valid: true}}).Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`slug` = ? AND `posts`.`draft` = ?", query)
+	assert.Equal(t, []interface{}{sql.NullString{String: "a", Valid: true}, false}, values)
+
+	query, _ = newDB(conn).Table("posts").WhereStruct((*postFilter)(nil)).Query()
+	assert.Equal(t, "SELECT * FROM `posts`", query)
+}
+
+func TestDB_WhereMap(t *testing.T) {
+	var missing *int
+	query, values := newDB(conn).Table("users").WhereMap(map[string]interface{}{
+		"status":        []string{"active", "new"},
+		"points,op=gte": 10,
+		"name,op=ne":    "",
+		"age":           missing,
+		"org":           nil,
+	}).Query()
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`name` <> ? AND `users`.`points` >= ? AND `users`.`status` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{"", 10, "active", "new"}, values)
+}
+
+func TestDB_WhereFilterErrors(t *testing.T) {
+	_, _, err := newDB(conn).Table("users").WhereMap(map[string]interface{}{"points,op=approx": 1}).ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, _, err = newDB(conn).Table("users").WhereMap(map[string]interface{}{"points,op=in": 1}).ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, _, err = newDB(conn).Table("users").WhereMap(map[string]interface{}{"id": []int{}}).ToSQL()
+	assert.ErrorIs(t, err, ErrEmptyWhereInValues)
+
+	_, _, err = newDB(conn).Table("users").WhereStruct(1).ToSQL()
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, _, err = newDB(conn).Table("users").AllowColumns("id").WhereMap(map[string]interface{}{"password": "x"}).ToSQL()
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+}
@@ -0,0 +1,153 @@
+package buildsqlx
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Normalized returns the shape of the statement: placeholders of any style, string and numeric literals
+// are replaced with ?, lists of placeholders (IN lists, value rows) are collapsed to (...), repeating
+// row groups and CASE arms are kept once, comments but optimizer hints are stripped,
+// unquoted words are upper cased and whitespace is normalised
+func (s Statement) Normalized() string {
+	tokens := tokenize(s.SQL)
+
+	normalized := tokens[:0]
+	for _, t := range tokens {
+		t.text = normalizeToken(t.text)
+		if t.text != "" {
+			normalized = append(normalized, t)
+		}
+	}
+
+	return formatTokens(collapseRepeats(collapseLists(normalized)), FormatOptions{})
+}
+
+// Fingerprint returns a stable hash of the normalised statement, statements of the same shape share it
+// whatever their bindings, lengths of IN lists or amounts of inserted rows are
+func (s Statement) Fingerprint() string {
+	h := fnv.New64a()
+	h.Write([]byte(s.Normalized()))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// normalizeToken replaces literals and placeholders with ?, comments are replaced with an empty string
+func normalizeToken(text string) string {
+	c := text[0]
+	switch {
+	case strings.HasPrefix(text, "/*+"):
+		return text
+	case strings.HasPrefix(text, "/*") || strings.HasPrefix(text, "--"):
+		return ""
+	case c == '\'' || (c >= '0' && c <= '9'):
+		return "?"
+	case c == '$' && len(text) > 1 && text[1] >= '0' && text[1] <= '9':
+		// PostgreSQL $1
+		return "?"
+	case c == '@' && len(text) > 1 && text[1] != '@':
+		// SQL Server @p1 and named params, but @@system variables
+		return "?"
+	case isWordByte(c):
+		return strings.ToUpper(text)
+	}
+	return text
+}
+
+// collapseLists replaces parentheses holding placeholders only with (...)
+func collapseLists(tokens []token) []token {
+	var out []token
+	for i := 0; i < len(tokens); i++ {
+		out = append(out, tokens[i])
+		if tokens[i].text != "(" {
+			continue
+		}
+
+		j := i + 1
+		for j < len(tokens) && (tokens[j].text == "?" || tokens[j].text == ",") {
+			j++
+		}
+		if j > i+1 && j < len(tokens) && tokens[j].text == ")" {
+			out = append(out, token{text: "..."}, tokens[j])
+			i = j
+		}
+	}
+	return out
+}
+
+// collapseRepeats drops parentheses groups repeating the previous one after a comma, AND or OR,
+// and CASE arms repeating the previous one
+func collapseRepeats(tokens []token) []token {
+	var out []token
+	for i := 0; i < len(tokens); {
+		unit, end := unitAt(tokens, i)
+		out = append(out, unit...)
+
+		for {
+			j := end
+			if tokens[i].text == "(" && j < len(tokens) && isRepeatSep(tokens[j].text) {
+				j++
+			} else if tokens[i].text != "WHEN" {
+				break
+			}
+			if j >= len(tokens) || tokens[j].text != tokens[i].text {
+				break
+			}
+
+			next, k := unitAt(tokens, j)
+			if unitKey(next) != unitKey(unit) {
+				break
+			}
+			end = k
+		}
+		i = end
+	}
+	return out
+}
+
+func isRepeatSep(text string) bool {
+	return text == "," || text == "AND" || text == "OR"
+}
+
+// unitAt returns the collapsed parentheses group or CASE arm starting at i or the single token
+// along with the index following it
+func unitAt(tokens []token, i int) ([]token, int) {
+	switch tokens[i].text {
+	case "(":
+		depth := 0
+		for j := i; j < len(tokens); j++ {
+			switch tokens[j].text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+				if depth == 0 {
+					unit := append([]token{tokens[i]}, collapseRepeats(tokens[i+1:j])...)
+					return append(unit, tokens[j]), j + 1
+				}
+			}
+		}
+	case "WHEN":
+		j := i + 1
+		for j < len(tokens) {
+			switch tokens[j].text {
+			case "WHEN", "ELSE", "END", ";":
+				return append([]token{tokens[i]}, collapseRepeats(tokens[i+1:j])...), j
+			case "(":
+				_, j = unitAt(tokens, j)
+				continue
+			}
+			j++
+		}
+		return tokens[i:], j
+	}
+	return tokens[i : i+1], i + 1
+}
+
+func unitKey(unit []token) string {
+	texts := make([]string, len(unit))
+	for i, t := range unit {
+		texts[i] = t.text
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,47 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatement_Normalized(t *testing.T) {
+	query, values := newDB(conn).Table("users").Where("id", OpEQ, 1).
+		AndWhereIn("status", "a", "b", "c").Limit(10).Query()
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`id` = ? AND `users`.`status` IN (...) LIMIT ?",
+		Statement{SQL: query, Args: values}.Normalized())
+
+	stmt := Statement{SQL: "select /* api */ name from users where  name = 'O''Brien' and id in ($1, $2) -- trailing"}
+	assert.Equal(t, "SELECT NAME FROM USERS WHERE NAME = ? AND ID IN (...)", stmt.Normalized())
+
+	query, _, err := newDB(conn).Table("users").UpdateRows([]string{"id"},
+		[]map[string]interface{}{{"id": 1, "name": "foo"}, {"id": 2, "name": "bar"}}, BatchCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name` = CASE WHEN `id` = ? THEN ? ELSE `name` END WHERE `users`.`id` IN (...)",
+		Statement{SQL: query}.Normalized())
+}
+
+func TestStatement_Fingerprint(t *testing.T) {
+	fingerprint := func(query string, values []interface{}) string {
+		return Statement{SQL: query, Args: values}.Fingerprint()
+	}
+
+	one, _, err := newDB(conn).Table("users").InsertBatch([]map[string]interface{}{{"name": "foo"}})
+	assert.NoError(t, err)
+	many, _, err := newDB(conn).Table("users").InsertBatch([]map[string]interface{}{{"name": "foo"}, {"name": "bar"}, {"name": "baz"}})
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint(one, nil), fingerprint(many, nil))
+	assert.Len(t, fingerprint(one, nil), 16)
+
+	rows := []Pairs{Cols("org", "id", "name").Values(1, 10, "foo"), Cols("org", "id", "name").Values(2, 10, "bar")}
+	composite, _, err := newDB(conn).Table("users").UpdateRows([]string{"org", "id"}, rows, BatchCase)
+	assert.NoError(t, err)
+	single, _, err := newDB(conn).Table("users").UpdateRows([]string{"org", "id"}, rows[:1], BatchCase)
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint(composite, nil), fingerprint(single, nil))
+
+	byID, _ := newDB(conn).Table("users").Where("id", OpEQ, 1).Query()
+	byName, _ := newDB(conn).Table("users").Where("name", OpEQ, "foo").Query()
+	assert.NotEqual(t, fingerprint(byID, nil), fingerprint(byName, nil))
+}
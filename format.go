@@ -0,0 +1,360 @@
+package buildsqlx
+
+import "strings"
+
+// FormatOptions sets the layout of Format output
+type FormatOptions struct {
+	// Multiline puts every clause on its own line, selected columns, assignments, value rows
+	// and table columns are aligned one per line, sub queries are indented
+	Multiline bool
+	// Indent is the indentation of nested lines, two spaces if empty
+	Indent string
+}
+
+// Format renders sql stmt with normalised whitespace: runs of spaces are collapsed to a single one
+// and there are no spaces inside parentheses or before commas. String literals, quoted identifiers
+// and comments are left intact. With Multiline set the stmt is laid out on several lines, ex.:
+//
+//	SELECT `id`,
+//	       `name`
+//	FROM `users`
+//	WHERE `users`.`active` = ?
+//	  AND `users`.`points` > ?
+//	ORDER BY `users`.`id` DESC
+func Format(query string, opts FormatOptions) string {
+	return formatTokens(tokenize(query), opts)
+}
+
+// formatTokens renders the tokens of sql stmt
+func formatTokens(tokens []token, opts FormatOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+
+	f := &formatter{opts: opts, tokens: tokens}
+	f.frames = []*frame{{kind: frameTop}}
+	f.format()
+	return strings.TrimSpace(f.sb.String())
+}
+
+// token is a lexeme of sql stmt, space is set if it was preceded by whitespace
+type token struct {
+	text  string
+	space bool
+}
+
+// tokenize splits sql stmt into words, quoted strings and identifiers, comments and punctuation
+func tokenize(query string) []token {
+	var tokens []token
+	space := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			i++
+			for i < len(query) {
+				if query[i] == closing {
+					// doubled quote is the escaped one
+					if i+1 < len(query) && query[i+1] == closing {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end
+			}
+		case c == '(' || c == ')' || c == ',' || c == ';':
+			i++
+		case isWordByte(c):
+			for i < len(query) && isWordByte(query[i]) {
+				i++
+			}
+		default:
+			for i < len(query) && !isWordByte(query[i]) && !strings.ContainsRune(" \t\n\r'\"`[(),;", rune(query[i])) {
+				i++
+			}
+		}
+
+		tokens = append(tokens, token{text: query[start:i], space: space})
+		space = false
+	}
+	return tokens
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c == '*' || c == '?' || c == '@' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+// phrase is a clause keyword of the multiline layout
+type phrase struct {
+	words []string
+	// start phrases begin statements, they are recognised as the first words of a statement only
+	start bool
+	// list phrases put each of their comma separated items on its own line
+	list bool
+	// cond phrases put each of their AND/OR conditions on its own line
+	cond bool
+	// inline phrases don't start a new line
+	inline bool
+	// ddl phrases start a statement, whose clauses are not broken but for the commas
+	ddl bool
+}
+
+// phrases are matched longest first
+var phrases = []phrase{
+	{words: []string{"INSERT", "OR", "IGNORE", "INTO"}, start: true},
+	{words: []string{"ON", "DUPLICATE", "KEY", "UPDATE"}, list: true},
+	{words: []string{"LOCK", "IN", "SHARE", "MODE"}},
+	{words: []string{"FOR", "NO", "KEY", "UPDATE"}},
+	{words: []string{"INSERT", "IGNORE", "INTO"}, start: true},
+	{words: []string{"LEFT", "OUTER", "JOIN"}},
+	{words: []string{"RIGHT", "OUTER", "JOIN"}},
+	{words: []string{"FULL", "OUTER", "JOIN"}},
+	{words: []string{"THEN", "UPDATE", "SET"}, list: true, inline: true},
+	{words: []string{"DO", "UPDATE", "SET"}, list: true},
+	{words: []string{"WHEN", "NOT", "MATCHED"}},
+	{words: []string{"FOR", "KEY", "SHARE"}},
+	{words: []string{"CREATE", "TABLE"}, start: true, ddl: true},
+	{words: []string{"ALTER", "TABLE"}, start: true, ddl: true, list: true},
+	{words: []string{"INSERT", "INTO"}, start: true},
+	{words: []string{"DELETE", "FROM"}, start: true},
+	{words: []string{"MERGE", "INTO"}, start: true},
+	{words: []string{"WHEN", "MATCHED"}},
+	{words: []string{"ON", "CONFLICT"}},
+	{words: []string{"GROUP", "BY"}},
+	{words: []string{"ORDER", "BY"}},
+	{words: []string{"UNION", "ALL"}},
+	{words: []string{"INNER", "JOIN"}},
+	{words: []string{"LEFT", "JOIN"}},
+	{words: []string{"RIGHT", "JOIN"}},
+	{words: []string{"FULL", "JOIN"}},
+	{words: []string{"CROSS", "JOIN"}},
+	{words: []string{"FOR", "UPDATE"}},
+	{words: []string{"FOR", "SHARE"}},
+	{words: []string{"SELECT"}, start: true, list: true},
+	{words: []string{"UPDATE"}, start: true},
+	{words: []string{"DELETE"}, start: true},
+	{words: []string{"UNION"}},
+	{words: []string{"JOIN"}},
+	{words: []string{"FROM"}},
+	{words: []string{"USING"}},
+	{words: []string{"WHERE"}, cond: true},
+	{words: []string{"HAVING"}, cond: true},
+	{words: []string{"SET"}, list: true},
+	{words: []string{"VALUES"}, list: true},
+	{words: []string{"OUTPUT"}},
+	{words: []string{"RETURNING"}},
+	{words: []string{"LIMIT"}},
+}
+
+// kinds of parentheses
+const (
+	frameTop    = iota
+	frameInline // function calls, IN lists, value rows, etc.
+	frameSub    // sub queries
+	frameBlock  // column definitions of CREATE TABLE
+)
+
+// frame is the state of the parentheses level
+type frame struct {
+	kind    int
+	level   int
+	started bool
+	ddl     bool
+	// clause flags
+	list, cond bool
+	align      string
+	between    bool
+}
+
+type formatter struct {
+	opts   FormatOptions
+	tokens []token
+	frames []*frame
+	sb     strings.Builder
+	// lineStart is set when nothing was written to the current line yet
+	lineStart bool
+}
+
+func (f *formatter) top() *frame {
+	return f.frames[len(f.frames)-1]
+}
+
+func (f *formatter) newline(indent string) {
+	if !f.opts.Multiline {
+		return
+	}
+	f.sb.WriteByte('\n')
+	f.sb.WriteString(indent)
+	f.lineStart = true
+}
+
+func (f *formatter) indent(level int) string {
+	return strings.Repeat(f.opts.Indent, level)
+}
+
+// write writes the token text separated from the previous one as it was in the stmt
+func (f *formatter) write(i int) {
+	t := f.tokens[i]
+	if t.space && !f.lineStart && i > 0 && f.tokens[i-1].text != "(" && t.text != ")" && t.text != "," {
+		f.sb.WriteByte(' ')
+	}
+	f.sb.WriteString(t.text)
+	f.lineStart = false
+}
+
+func (f *formatter) format() {
+	f.lineStart = true
+	for i := 0; i < len(f.tokens); {
+		fr := f.top()
+		t := f.tokens[i]
+
+		if fr.kind != frameInline && fr.kind != frameBlock && !(fr.ddl && fr.started) {
+			if p, ok := f.match(i, fr); ok {
+				i = f.writePhrase(i, p, fr)
+				continue
+			}
+		}
+
+		switch strings.ToUpper(t.text) {
+		case "(":
+			f.write(i)
+			kind := frameInline
+			switch {
+			case i+1 < len(f.tokens) && strings.EqualFold(f.tokens[i+1].text, "SELECT"):
+				kind = frameSub
+			case fr.kind == frameTop && fr.ddl && !fr.list:
+				// the first parentheses of CREATE TABLE hold the columns
+				kind = frameBlock
+			}
+
+			child := &frame{kind: kind, level: fr.level}
+			if kind != frameInline {
+				child.level++
+				f.newline(f.indent(child.level))
+			}
+			f.frames = append(f.frames, child)
+			fr.started = true
+			i++
+			continue
+		case ")":
+			if len(f.frames) > 1 {
+				f.frames = f.frames[:len(f.frames)-1]
+				if fr.kind == frameSub || fr.kind == frameBlock {
+					f.newline(f.indent(f.top().level))
+				}
+			}
+			f.write(i)
+			i++
+			continue
+		case ",":
+			f.write(i)
+			switch {
+			case fr.kind == frameBlock:
+				f.newline(f.indent(fr.level))
+			case fr.kind != frameInline && fr.list:
+				f.newline(fr.align)
+			}
+			i++
+			continue
+		case "BETWEEN":
+			fr.between = true
+		case "AND", "OR":
+			if fr.cond && fr.kind != frameInline {
+				if fr.between && strings.EqualFold(t.text, "AND") {
+					fr.between = false
+				} else {
+					f.newline(f.indent(fr.level) + f.opts.Indent)
+				}
+			}
+		}
+
+		f.write(i)
+		fr.started = true
+		i++
+	}
+}
+
+// match finds the longest phrase starting at i
+func (f *formatter) match(i int, fr *frame) (phrase, bool) {
+	for _, p := range phrases {
+		if p.start && fr.started {
+			continue
+		}
+		if i+len(p.words) > len(f.tokens) {
+			continue
+		}
+
+		ok := true
+		for k, w := range p.words {
+			if !strings.EqualFold(f.tokens[i+k].text, w) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return p, true
+		}
+	}
+	return phrase{}, false
+}
+
+// writePhrase starts the clause of the phrase at i returning the index of the token following it
+func (f *formatter) writePhrase(i int, p phrase, fr *frame) int {
+	if !p.inline && !f.lineStart {
+		f.newline(f.indent(fr.level))
+	}
+
+	width := 0
+	for k := range p.words {
+		f.write(i + k)
+		width += len(p.words[k]) + 1
+	}
+
+	fr.list, fr.cond, fr.between = p.list, p.cond, false
+	fr.align = f.indent(fr.level) + strings.Repeat(" ", width)
+	if p.ddl {
+		fr.ddl = true
+		// ALTER TABLE actions are indented below the stmt
+		fr.align = f.indent(fr.level + 1)
+	}
+	if p.inline {
+		fr.align = f.indent(fr.level + 1)
+	}
+
+	switch p.words[0] {
+	case "UNION":
+		// the next select starts a statement on its own line
+		fr.started = false
+		f.newline(f.indent(fr.level))
+	default:
+		fr.started = true
+	}
+
+	return i + len(p.words)
+}
@@ -0,0 +1,67 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat_Whitespace(t *testing.T) {
+	query := Format("SELECT  a ,  b\n FROM   t WHERE a IN ( 1 , 2 )  AND 'x  y' = `a  b`", FormatOptions{})
+	assert.Equal(t, "SELECT a, b FROM t WHERE a IN (1, 2) AND 'x  y' = `a  b`", query)
+}
+
+func TestFormat_Select(t *testing.T) {
+	query, _ := newDB(conn).Table("users").Select("id", "name AS author").
+		LeftJoin("posts", "users.id", "=", "posts.user_id").
+		Where("active", OpEQ, 1).OrWhereBetween("points", 1, 10).
+		GroupBy("id").OrderBy("id", "desc").Limit(10).Offset(20).Query()
+
+	assert.Equal(t, "SELECT `id`,\n"+
+		"       `name` AS `author`\n"+
+		"FROM `users`\n"+
+		"LEFT JOIN `posts` ON `users`.`id` = `posts`.`user_id`\n"+
+		"WHERE `users`.`active` = ?\n"+
+		"  OR `users`.`points` BETWEEN ? AND ?\n"+
+		"GROUP BY `id`\n"+
+		"ORDER BY `users`.`id` DESC\n"+
+		"LIMIT 10 OFFSET 20", Format(query, FormatOptions{Multiline: true}))
+}
+
+func TestFormat_Union(t *testing.T) {
+	db := newDB(conn)
+	db.Table("users").Select("name").Union()
+	query, _ := db.Table("posts").Select("title").Count()
+
+	assert.Equal(t, "SELECT COUNT(*)\n"+
+		"FROM (\n"+
+		"\tSELECT `name`\n"+
+		"\tFROM `users`\n"+
+		"\tUNION\n"+
+		"\tSELECT `title`\n"+
+		"\tFROM `posts`\n"+
+		") AS sub", Format(query, FormatOptions{Multiline: true, Indent: "\t"}))
+}
+
+func TestFormat_Insert(t *testing.T) {
+	query, _, err := newDB(conn).Table("users").InsertBatch([]map[string]interface{}{{"name": "foo"}, {"name": "bar"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`name`)\n"+
+		"VALUES (?),\n"+
+		"       (?)", Format(query, FormatOptions{Multiline: true}))
+}
+
+func TestFormat_CreateTable(t *testing.T) {
+	sql, err := newDB(conn).CreateTable("users", func(table *Table) error {
+		table.Increments("id")
+		table.String("name", 20).Default("a, (b)")
+		table.TableComment("users")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `users` (\n"+
+		"  `id` INTEGER AUTO_INCREMENT,\n"+
+		"  `name` VARCHAR(20) DEFAULT 'a, (b)',\n"+
+		"  PRIMARY KEY (`id`)\n"+
+		") COMMENT 'users'", Format(sql[0], FormatOptions{Multiline: true}))
+}
@@ -2,11 +2,7 @@ module github.com/go-kenka/buildsqlx
 
 go 1.18
 
-require (
-	github.com/huandu/go-clone v1.3.2
-	github.com/spf13/cast v1.5.0
-	github.com/stretchr/testify v1.8.0
-)
+require github.com/stretchr/testify v1.8.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
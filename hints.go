@@ -0,0 +1,106 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidHint is recorded for optimizer hints which aren't NAME or NAME(args)
+var ErrInvalidHint = errors.New("sql: invalid optimizer hint")
+
+// optimizerHint matches NAME or NAME(args), args can't close the hints comment or nest parentheses
+var optimizerHint = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([^()*/;]*\))?$`)
+
+// index hint kinds
+const (
+	indexUse    = "USE"
+	indexForce  = "FORCE"
+	indexIgnore = "IGNORE"
+)
+
+// indexHint is the index hint placed after the table name
+type indexHint struct {
+	kind    string
+	indexes []string
+}
+
+// UseIndex suggests the indexes to look up rows with (MySQL),
+// rendered as INDEX() table hint on SQL Server and ignored by other dialects
+func (r *DB) UseIndex(indexes ...string) *DB {
+	r.Builder.indexHints = append(r.Builder.indexHints, &indexHint{kind: indexUse, indexes: indexes})
+	return r
+}
+
+// ForceIndex forces the indexes to look up rows with (MySQL),
+// rendered as INDEX() table hint on SQL Server and ignored by other dialects
+func (r *DB) ForceIndex(indexes ...string) *DB {
+	r.Builder.indexHints = append(r.Builder.indexHints, &indexHint{kind: indexForce, indexes: indexes})
+	return r
+}
+
+// IgnoreIndex prevents the indexes from being used to look up rows (MySQL), ignored by other dialects
+func (r *DB) IgnoreIndex(indexes ...string) *DB {
+	r.Builder.indexHints = append(r.Builder.indexHints, &indexHint{kind: indexIgnore, indexes: indexes})
+	return r
+}
+
+// Hint adds optimizer hints placed after SELECT keyword (MySQL), ex.:
+// Hint("MAX_EXECUTION_TIME(1000)") renders SELECT /*+ MAX_EXECUTION_TIME(1000) */ ...
+// Hints are ignored by other dialects. Hints which aren't NAME or NAME(args) are skipped and ErrInvalidHint is set
+func (r *DB) Hint(hints ...string) *DB {
+	for _, hint := range hints {
+		hint = strings.TrimSpace(hint)
+		if !optimizerHint.MatchString(hint) {
+			r.Builder.setErr(fmt.Errorf("%w: %q", ErrInvalidHint, hint))
+			continue
+		}
+		r.Builder.hints = append(r.Builder.hints, hint)
+	}
+	return r
+}
+
+// composeHints writes optimizer hints comment after SELECT keyword
+func (r *builder) composeHints() {
+	if len(r.hints) == 0 || !isMySQL(r.dialect) {
+		return
+	}
+
+	r.WriteString("/*+").Pad().WriteString(strings.Join(r.hints, " ")).Pad().WriteString("*/").Pad()
+}
+
+// composeTableHints writes index hints (MySQL) or table hints (SQL Server) after the table name
+func (r *builder) composeTableHints() {
+	switch {
+	case isMySQL(r.dialect):
+		for _, h := range r.indexHints {
+			r.Pad().WriteString(h.kind).Pad().WriteString("INDEX").Pad().Nested(func(s *sqlBuilder) {
+				for i, idx := range h.indexes {
+					if i > 0 {
+						s.Comma()
+					}
+					s.Ident(idx)
+				}
+			})
+		}
+	case r.dialect == DialectSQLServer:
+		var hints []string
+		for _, h := range r.indexHints {
+			if h.kind == indexIgnore {
+				continue
+			}
+
+			idx := make([]string, len(h.indexes))
+			for i := range h.indexes {
+				idx[i] = r.Quote(h.indexes[i])
+			}
+			hints = append(hints, "INDEX("+strings.Join(idx, ", ")+")")
+		}
+		hints = append(hints, r.lockHints()...)
+
+		if len(hints) > 0 {
+			r.Pad().WriteString("WITH").Pad().WriteByte('(').WriteString(strings.Join(hints, ", ")).WriteByte(')')
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_IndexHints(t *testing.T) {
+	build := func(driver string) string {
+		query, _ := newDB(&Connection{driver: driver}).Table("orders").
+			Hint("MAX_EXECUTION_TIME(1000)", "NO_ICP(orders)").
+			ForceIndex("idx_user", "idx_created").
+			IgnoreIndex("idx_status").
+			Where("user_id", OpEQ, 1).
+			Query()
+		return query
+	}
+
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_ICP(orders) */ * FROM `orders` FORCE INDEX (`idx_user`, `idx_created`) IGNORE INDEX (`idx_status`) WHERE `orders`.`user_id` = ?", build("mysql"))
+	assert.Equal(t, `SELECT * FROM "orders" WHERE "orders"."user_id" = ?`, build("postgres"))
+	assert.Equal(t, `SELECT * FROM [orders] WITH (INDEX([idx_user], [idx_created])) WHERE [orders].[user_id] = ?`, build("sqlserver"))
+
+	query, _ := newDB(&Connection{driver: "sqlserver"}).Table("orders").UseIndex("idx_user").LockForUpdate().Query()
+	assert.Equal(t, `SELECT * FROM [orders] WITH (INDEX([idx_user]), UPDLOCK, ROWLOCK)`, query)
+}
+
+func TestDB_HintInvalid(t *testing.T) {
+	db := newDB(conn).Table("users").Hint("x */ ; DROP TABLE users; /*", "BKA(users)")
+	query, _ := db.Query()
+	assert.Equal(t, "SELECT /*+ BKA(users) */ * FROM `users`", query)
+	assert.ErrorIs(t, db.Err(), ErrInvalidHint)
+
+	db = newDB(conn).Table("users").Hint("SET_VAR(sort_buffer_size = 16M)", "NO_RANGE_OPTIMIZATION(users PRIMARY)")
+	query, _ = db.Query()
+	assert.Equal(t, "SELECT /*+ SET_VAR(sort_buffer_size = 16M) NO_RANGE_OPTIMIZATION(users PRIMARY) */ * FROM `users`", query)
+	assert.NoError(t, db.Err())
+}
@@ -0,0 +1,69 @@
+package buildsqlx
+
+import "errors"
+
+var ErrIgnoreUnsupported = errors.New("sql: insert ignoring conflicts is not supported by the dialect")
+
+// InsertFrom inserts the rows selected by sub query: INSERT INTO table (cols) SELECT ...
+// Columns list is omitted if cols is empty
+func (r *DB) InsertFrom(cols []string, sub *DB) (query string, values []interface{}) {
+	builder := r.Builder
+	if builder.table == "" {
+		builder.setErr(ErrNoTable)
+		return
+	}
+
+	subQuery, subValues := sub.Query()
+	if err := sub.Err(); err != nil {
+		builder.setErr(err)
+		return
+	}
+
+	builder.WriteString(insertInto).Pad().Ident(builder.table)
+	if len(cols) > 0 {
+		builder.Pad().Nested(func(s *sqlBuilder) {
+			s.idents(cols)
+		})
+	}
+	builder.composeOutput(outputInserted)
+	builder.Pad().WriteString(subQuery)
+	builder.composeReturning("INSERT")
+
+	query = builder.String()
+	values = append(values, subValues...)
+	return
+}
+
+// InsertIgnore inserts one row skipping it if it conflicts with an existing one, see InsertIgnoreBatch
+func (r *DB) InsertIgnore(data map[string]interface{}) (query string, values []interface{}, err error) {
+	return r.InsertIgnoreBatch([]map[string]interface{}{data})
+}
+
+// InsertIgnoreBatch inserts multiple rows skipping the ones conflicting with existing rows, it renders:
+// INSERT IGNORE (MySQL), INSERT OR IGNORE (SQLite) or INSERT ... ON CONFLICT DO NOTHING (PostgreSQL)
+func (r *DB) InsertIgnoreBatch(data []map[string]interface{}) (query string, values []interface{}, err error) {
+	builder := r.Builder
+	if builder.table == "" {
+		return "", nil, ErrNoTable
+	}
+
+	columns, err := prepareInsertBatch(data, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch {
+	case isMySQL(builder.dialect):
+		values = builder.writeInsertBatch(insertIgnoreInto, columns, data)
+	case builder.dialect == DialectSQLite:
+		values = builder.writeInsertBatch(insertOrIgnoreInto, columns, data)
+	case builder.dialect == DialectPostgres:
+		values = builder.writeInsertBatch(insertInto, columns, data)
+		builder.Pad().WriteString("ON CONFLICT DO NOTHING")
+	default:
+		return "", nil, ErrIgnoreUnsupported
+	}
+	builder.composeReturning("INSERT")
+
+	return r.built(values)
+}
@@ -0,0 +1,53 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_InsertFrom(t *testing.T) {
+	sub := newDB(conn).Table("users").Select("id", "email").Where("active", OpEQ, 1)
+	query, values := newDB(conn).Table("subscribers").InsertFrom([]string{"user_id", "email"}, sub)
+	assert.Equal(t, "INSERT INTO `subscribers` (`user_id`, `email`) SELECT `id`, `email` FROM `users` WHERE `users`.`active` = ?", query)
+	assert.Equal(t, []interface{}{1}, values)
+}
+
+func TestDB_InsertFromSubErr(t *testing.T) {
+	db := newDB(conn).Table("archive")
+	query, _ := db.InsertFrom([]string{"id"}, newDB(conn).Select("id"))
+	assert.Empty(t, query)
+	assert.ErrorIs(t, db.Err(), ErrNoTable)
+
+	db = newDB(conn).Table("archive")
+	sub := newDB(conn).Table("posts").AllowColumns("id").Select("id").Where("secret", OpEQ, 1)
+	query, _ = db.InsertFrom([]string{"id"}, sub)
+	assert.Empty(t, query)
+	assert.ErrorIs(t, db.Err(), ErrColumnNotAllowed)
+}
+
+func TestDB_InsertIgnore(t *testing.T) {
+	data := map[string]interface{}{"email": "a@b.c", "name": "John"}
+	tests := []struct {
+		driver  string
+		wantSql string
+		wantErr error
+	}{
+		{driver: "mysql", wantSql: "INSERT IGNORE INTO `users` (`email`, `name`) VALUES (?, ?)"},
+		{driver: "sqlite3", wantSql: `INSERT OR IGNORE INTO "users" ("email", "name") VALUES (?, ?)`},
+		{driver: "postgres", wantSql: `INSERT INTO "users" ("email", "name") VALUES (?, ?) ON CONFLICT DO NOTHING`},
+		{driver: "sqlserver", wantErr: ErrIgnoreUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			query, values, err := newDB(&Connection{driver: tt.driver}).Table("users").InsertIgnore(data)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSql, query)
+			assert.Equal(t, []interface{}{"a@b.c", "John"}, values)
+		})
+	}
+}
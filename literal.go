@@ -0,0 +1,92 @@
+package buildsqlx
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLiteral is returned when the value can't be rendered as sql literal
+var ErrInvalidLiteral = errors.New("sql: value can't be rendered as literal")
+
+// quoteString quotes s as a string literal of the dialect, quotes inside it are doubled
+// and MySQL backslashes are escaped, so s can't terminate the literal
+func quoteString(dialect, s string) string {
+	if isMySQL(dialect) {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// literal renders the value as sql literal of the dialect, Expr is rendered as is
+func literal(dialect string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case Expr:
+		if len(v.args) > 0 {
+			return "", fmt.Errorf("%w: expression with bindings", ErrInvalidLiteral)
+		}
+		if v.col != "" {
+			return quoteIdent(dialect, v.col) + v.sql, nil
+		}
+		return v.sql, nil
+	case string:
+		return quoteString(dialect, v), nil
+	case []byte:
+		switch dialect {
+		case DialectPostgres:
+			return `'\x` + hex.EncodeToString(v) + "'", nil
+		case DialectSQLServer:
+			return "0x" + hex.EncodeToString(v), nil
+		default:
+			return "X'" + hex.EncodeToString(v) + "'", nil
+		}
+	case bool:
+		switch {
+		case isMySQL(dialect) || dialect == DialectPostgres:
+			if v {
+				return "TRUE", nil
+			}
+			return "FALSE", nil
+		case v:
+			return "1", nil
+		default:
+			return "0", nil
+		}
+	case time.Time:
+		return quoteString(dialect, v.Format("2006-01-02 15:04:05.999999")), nil
+	case driver.Valuer:
+		dv, err := v.Value()
+		if err != nil {
+			return "", err
+		}
+		return literal(dialect, dv)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return quoteString(dialect, rv.String()), nil
+	case reflect.Bool:
+		return literal(dialect, rv.Bool())
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "NULL", nil
+		}
+		return literal(dialect, rv.Elem().Interface())
+	}
+
+	return "", fmt.Errorf("%w: %T", ErrInvalidLiteral, v)
+}
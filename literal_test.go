@@ -0,0 +1,73 @@
+package buildsqlx
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiteral(t *testing.T) {
+	type status string
+
+	tests := []struct {
+		dialect string
+		value   interface{}
+		want    string
+	}{
+		{DialectMySQL, "it's", `'it''s'`},
+		{DialectMySQL, `\'; DROP TABLE users; --`, `'\\''; DROP TABLE users; --'`},
+		{DialectPostgres, `it's \`, `'it''s \'`},
+		{DialectMySQL, nil, "NULL"},
+		{DialectMySQL, true, "TRUE"},
+		{DialectSQLServer, true, "1"},
+		{DialectSQLite, false, "0"},
+		{DialectMySQL, int8(-3), "-3"},
+		{DialectMySQL, uint(7), "7"},
+		{DialectMySQL, 1.5, "1.5"},
+		{DialectMySQL, status("new"), "'new'"},
+		{DialectMySQL, Raw(CurrentTimestamp), "CURRENT_TIMESTAMP"},
+		{DialectMySQL, []byte{0xca, 0xfe}, "X'cafe'"},
+		{DialectPostgres, []byte{0xca, 0xfe}, `'\xcafe'`},
+		{DialectMySQL, sql.NullString{}, "NULL"},
+		{DialectMySQL, time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC), "'2022-01-02 03:04:05'"},
+	}
+	for _, tt := range tests {
+		got, err := literal(tt.dialect, tt.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := literal(DialectMySQL, struct{}{})
+	assert.ErrorIs(t, err, ErrInvalidLiteral)
+
+	_, err = literal(DialectMySQL, Raw("NOW() + ?", 1))
+	assert.ErrorIs(t, err, ErrInvalidLiteral)
+}
+
+func TestTable_Literals(t *testing.T) {
+	sql, err := newDB(conn).CreateTable("users", func(table *Table) error {
+		table.String("name", 20).Default("O'Brien").Comment("user's name")
+		table.Boolean("active").Default(true)
+		table.DateTime("created_at").Default(Raw(CurrentTimestamp))
+		table.TableComment("it's a table")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CREATE TABLE `users` (`name` VARCHAR(20) DEFAULT 'O''Brien' COMMENT 'user''s name', " +
+		"`active` TINYINT DEFAULT TRUE, `created_at` DATETIME DEFAULT CURRENT_TIMESTAMP) COMMENT 'it''s a table'"}, sql)
+
+	_, err = newDB(conn).CreateTable("users", func(table *Table) error {
+		table.String("name", 20).Default(struct{}{})
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidLiteral)
+
+	cascade := "CASCADE; DROP TABLE users"
+	_, err = newDB(conn).CreateTable("posts", func(table *Table) error {
+		table.BigInt("user_id").ForeignKey("fk_user", "users", "id", &cascade, nil)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidReferentialAction)
+}
@@ -0,0 +1,149 @@
+package buildsqlx
+
+// row lock strengths
+const (
+	lockUpdate = iota
+	lockNoKeyUpdate
+	lockShare
+	lockKeyShare
+)
+
+// row lock waiting policies
+const (
+	lockWait       = ""
+	lockNoWait     = "NOWAIT"
+	lockSkipLocked = "SKIP LOCKED"
+)
+
+// lock is the locking clause of select statement
+type lock struct {
+	strength int
+	of       []string
+	wait     string
+}
+
+// LockForUpdate locks selected rows as FOR UPDATE
+func (r *DB) LockForUpdate() *DB {
+	r.lock().strength = lockUpdate
+	return r
+}
+
+// LockForNoKeyUpdate locks selected rows as FOR NO KEY UPDATE (PostgreSQL),
+// falls back to FOR UPDATE on other dialects
+func (r *DB) LockForNoKeyUpdate() *DB {
+	r.lock().strength = lockNoKeyUpdate
+	return r
+}
+
+// LockForShare locks selected rows as FOR SHARE (LOCK IN SHARE MODE on MySQL)
+func (r *DB) LockForShare() *DB {
+	r.lock().strength = lockShare
+	return r
+}
+
+// LockForKeyShare locks selected rows as FOR KEY SHARE (PostgreSQL),
+// falls back to shared lock on other dialects
+func (r *DB) LockForKeyShare() *DB {
+	r.lock().strength = lockKeyShare
+	return r
+}
+
+// LockOf restricts the lock to rows of the tables given, FOR UPDATE is implied if no lock is set
+func (r *DB) LockOf(tables ...string) *DB {
+	l := r.lock()
+	l.of = append(l.of, tables...)
+	return r
+}
+
+// NoWait fails the statement instead of waiting for locked rows, FOR UPDATE is implied if no lock is set
+func (r *DB) NoWait() *DB {
+	r.lock().wait = lockNoWait
+	return r
+}
+
+// SkipLocked skips locked rows instead of waiting for them, FOR UPDATE is implied if no lock is set.
+// Useful for job queue consumers: SELECT ... LIMIT 10 FOR UPDATE SKIP LOCKED
+func (r *DB) SkipLocked() *DB {
+	r.lock().wait = lockSkipLocked
+	return r
+}
+
+// lock returns the builder lock clause creating FOR UPDATE one if there is none
+func (r *DB) lock() *lock {
+	if r.Builder.lock == nil {
+		r.Builder.lock = &lock{strength: lockUpdate}
+	}
+	return r.Builder.lock
+}
+
+// composeLock writes the locking clause at the end of select statement
+func (r *builder) composeLock() {
+	l := r.lock
+	if l == nil {
+		return
+	}
+
+	switch {
+	case r.dialect == DialectPostgres:
+		switch l.strength {
+		case lockNoKeyUpdate:
+			r.Pad().WriteString("FOR NO KEY UPDATE")
+		case lockShare:
+			r.Pad().WriteString("FOR SHARE")
+		case lockKeyShare:
+			r.Pad().WriteString("FOR KEY SHARE")
+		default:
+			r.Pad().WriteString("FOR UPDATE")
+		}
+	case isMySQL(r.dialect):
+		switch {
+		case l.strength == lockUpdate || l.strength == lockNoKeyUpdate:
+			r.Pad().WriteString("FOR UPDATE")
+		case len(l.of) > 0 || l.wait != lockWait:
+			// the modifiers are only supported by MySQL 8 syntax
+			r.Pad().WriteString("FOR SHARE")
+		default:
+			r.Pad().WriteString("LOCK IN SHARE MODE")
+			return
+		}
+	default:
+		// SQLite has no row locks, SQL Server locks with table hints
+		return
+	}
+
+	if len(l.of) > 0 {
+		r.Pad().WriteString("OF").Pad()
+		for i, tbl := range l.of {
+			if i > 0 {
+				r.Comma()
+			}
+			r.Ident(tbl)
+		}
+	}
+
+	if l.wait != lockWait {
+		r.Pad().WriteString(l.wait)
+	}
+}
+
+// lockHints returns SQL Server table hints standing for the lock clause
+func (r *builder) lockHints() []string {
+	l := r.lock
+	if l == nil || r.dialect != DialectSQLServer {
+		return nil
+	}
+
+	hints := []string{"UPDLOCK", "ROWLOCK"}
+	if l.strength == lockShare || l.strength == lockKeyShare {
+		hints[0] = "HOLDLOCK"
+	}
+
+	switch l.wait {
+	case lockNoWait:
+		hints = append(hints, "NOWAIT")
+	case lockSkipLocked:
+		hints = append(hints, "READPAST")
+	}
+
+	return hints
+}
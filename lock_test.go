@@ -0,0 +1,72 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Lock(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		build   func(d *DB) *DB
+		wantSql string
+	}{
+		{
+			name:    "mysql for update",
+			driver:  "mysql",
+			build:   func(d *DB) *DB { return d.LockForUpdate() },
+			wantSql: "SELECT * FROM `jobs` LIMIT 10 FOR UPDATE",
+		},
+		{
+			name:    "mysql skip locked",
+			driver:  "mysql",
+			build:   func(d *DB) *DB { return d.LockForUpdate().SkipLocked() },
+			wantSql: "SELECT * FROM `jobs` LIMIT 10 FOR UPDATE SKIP LOCKED",
+		},
+		{
+			name:    "mysql share",
+			driver:  "mysql",
+			build:   func(d *DB) *DB { return d.LockForShare() },
+			wantSql: "SELECT * FROM `jobs` LIMIT 10 LOCK IN SHARE MODE",
+		},
+		{
+			name:    "mysql share nowait",
+			driver:  "mysql",
+			build:   func(d *DB) *DB { return d.LockForShare().NoWait() },
+			wantSql: "SELECT * FROM `jobs` LIMIT 10 FOR SHARE NOWAIT",
+		},
+		{
+			name:    "postgres no key update of",
+			driver:  "postgres",
+			build:   func(d *DB) *DB { return d.LockForNoKeyUpdate().LockOf("jobs").SkipLocked() },
+			wantSql: `SELECT * FROM "jobs" LIMIT 10 FOR NO KEY UPDATE OF "jobs" SKIP LOCKED`,
+		},
+		{
+			name:    "postgres key share",
+			driver:  "postgres",
+			build:   func(d *DB) *DB { return d.LockForKeyShare() },
+			wantSql: `SELECT * FROM "jobs" LIMIT 10 FOR KEY SHARE`,
+		},
+		{
+			name:    "sqlite",
+			driver:  "sqlite3",
+			build:   func(d *DB) *DB { return d.LockForUpdate() },
+			wantSql: `SELECT * FROM "jobs" LIMIT 10`,
+		},
+		{
+			name:    "sqlserver",
+			driver:  "sqlserver",
+			build:   func(d *DB) *DB { return d.SkipLocked() },
+			wantSql: `SELECT * FROM [jobs] WITH (UPDLOCK, ROWLOCK, READPAST) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDB(&Connection{driver: tt.driver}).Table("jobs").Limit(10)
+			query, _ := tt.build(d).Query()
+			assert.Equal(t, tt.wantSql, query)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package buildsqlx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidPerPage       = errors.New("sql: per page amount must be greater than zero")
+	ErrNoCursorColumns      = errors.New("sql: cursor pagination needs at least one order column")
+	ErrMixedCursorDirection = errors.New("sql: cursor order columns must share the same direction")
+	ErrCursorMismatch       = errors.New("sql: cursor values don't match cursor order columns")
+	ErrInvalidCursor        = errors.New("sql: cursor is malformed")
+)
+
+// Page holds the query fetching a single page of rows
+// and the query counting all rows matched regardless of pagination
+type Page struct {
+	Number      int64
+	PerPage     int64
+	Query       string
+	Values      []interface{}
+	CountQuery  string
+	CountValues []interface{}
+}
+
+// LastPage returns the number of the last page for total amount of rows
+func (p *Page) LastPage(total int64) int64 {
+	if total <= 0 {
+		return 1
+	}
+
+	return (total + p.PerPage - 1) / p.PerPage
+}
+
+// Paginate builds the query for page (numbered from 1) of perPage rows
+// along with the total count query for the same conditions
+func (r *DB) Paginate(page, perPage int64) (*Page, error) {
+	if perPage < 1 {
+		return nil, ErrInvalidPerPage
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	p := &Page{Number: page, PerPage: perPage}
+
+	counter := &DB{Builder: r.Builder.fork(), Conn: r.Conn}
+	counter.Builder.limit = 0
+	counter.Builder.offset = 0
+	p.CountQuery, p.CountValues = counter.Count()
+
+	p.Query, p.Values = r.Limit(perPage).Offset((page - 1) * perPage).Query()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Cursor is an opaque position in keyset paginated results
+type Cursor struct {
+	// Values of the order columns of the row the page starts after (or before)
+	Values []interface{} `json:"v"`
+	// Before set to true points to the rows preceding Values
+	Before bool `json:"b,omitempty"`
+}
+
+// NextCursor encodes the cursor to the page following the row with order column values
+func NextCursor(values ...interface{}) string {
+	return Cursor{Values: values}.Encode()
+}
+
+// PrevCursor encodes the cursor to the page preceding the row with order column values
+func PrevCursor(values ...interface{}) string {
+	return Cursor{Values: values, Before: true}.Encode()
+}
+
+// Encode returns the url safe representation of the cursor
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses the cursor returned by Encode, NextCursor or PrevCursor
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	c := &Cursor{}
+	if err = dec.Decode(c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// json numbers are bound as integers whenever possible
+	for i, v := range c.Values {
+		if n, ok := v.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				c.Values[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				c.Values[i] = fv
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// CursorPaginate builds keyset paginated query ordered by orderCols, prefix a column with "-"
+// to sort descending, e.g.: CursorPaginate(cursor, "-created_at", "-id").
+// An empty cursor starts from the first page, the page size is set by Limit. Columns are compared as row values,
+// which SQL Server lacks, so it gets the equivalent ORed comparisons.
+// Rows fetched with a PrevCursor come in reverse order and should be reversed by the caller
+func (r *DB) CursorPaginate(cursor string, orderCols ...string) (query string, values []interface{}, err error) {
+	if len(orderCols) == 0 {
+		return "", nil, ErrNoCursorColumns
+	}
+
+	cols := make([]string, len(orderCols))
+	desc := strings.HasPrefix(orderCols[0], "-")
+	for i, col := range orderCols {
+		if strings.HasPrefix(col, "-") != desc {
+			return "", nil, ErrMixedCursorDirection
+		}
+		cols[i] = strings.TrimPrefix(col, "-")
+	}
+
+	c := &Cursor{}
+	if cursor != "" {
+		c, err = DecodeCursor(cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(c.Values) != len(cols) {
+			return "", nil, ErrCursorMismatch
+		}
+	}
+
+	// walking backwards flips both the seek predicate and the ordering
+	if c.Before {
+		desc = !desc
+	}
+
+	op, direction := OpGT, "ASC"
+	if desc {
+		op, direction = OpLT, "DESC"
+	}
+
+	builder := r.Builder
+	if len(c.Values) > 0 {
+		builder.andWhereGroup(func(s *sqlBuilder) {
+			if len(cols) == 1 {
+				s.Column(builder.table, cols[0]).WriteOp(op).Arg(c.Values[0])
+				return
+			}
+
+			if builder.dialect == DialectSQLServer {
+				// no row values on SQL Server: (a > ? OR (a = ? AND b > ?))
+				s.Nested(func(s *sqlBuilder) {
+					for i := range cols {
+						if i > 0 {
+							s.WriteString(or)
+						}
+						s.Nested(func(s *sqlBuilder) {
+							for k, col := range cols[:i] {
+								s.Column(builder.table, col).WriteOp(OpEQ).Arg(c.Values[k])
+								s.WriteString(and)
+							}
+							s.Column(builder.table, cols[i]).WriteOp(op).Arg(c.Values[i])
+						})
+					}
+				})
+				return
+			}
+
+			s.Nested(func(s *sqlBuilder) {
+				for i, col := range cols {
+					if i > 0 {
+						s.Comma()
+					}
+					s.Column(builder.table, col)
+				}
+			}).WriteOp(op).Nested(func(s *sqlBuilder) {
+				s.Args(c.Values...)
+			})
+		})
+	}
+
+	builder.orderBy = make([]*orderBy, 0, len(cols))
+	builder.orderByRaw = nil
+	for _, col := range cols {
+		r.OrderBy(col, direction)
+	}
+
+	return r.ToSQL()
+}
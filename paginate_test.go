@@ -0,0 +1,82 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Paginate(t *testing.T) {
+	p, err := newDB(conn).Table("posts").Where("points", OpGT, 3).OrderBy("id", "DESC").Paginate(3, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`points` > ? ORDER BY `posts`.`id` DESC LIMIT 20 OFFSET 40", p.Query)
+	assert.Equal(t, []interface{}{3}, p.Values)
+	assert.Equal(t, "SELECT COUNT(*) FROM `posts` WHERE `posts`.`points` > ?", p.CountQuery)
+	assert.Equal(t, []interface{}{3}, p.CountValues)
+	assert.Equal(t, int64(5), p.LastPage(81))
+
+	_, err = newDB(conn).Table("posts").Paginate(1, 0)
+	assert.ErrorIs(t, err, ErrInvalidPerPage)
+}
+
+func TestDB_CursorPaginate(t *testing.T) {
+	// first page
+	query, values, err := newDB(conn).Table("posts").Where("status", OpEQ, "active").Limit(10).CursorPaginate("", "-created_at", "-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`status` = ? ORDER BY `posts`.`created_at` DESC, `posts`.`id` DESC LIMIT 10", query)
+	assert.Equal(t, []interface{}{"active"}, values)
+
+	// next page
+	query, values, err = newDB(conn).Table("posts").Where("status", OpEQ, "active").OrWhere("pinned", OpEQ, 1).Limit(10).
+		CursorPaginate(NextCursor("2022-01-01", 42), "-created_at", "-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `posts` WHERE (`posts`.`status` = ? OR `posts`.`pinned` = ?) AND (`posts`.`created_at`, `posts`.`id`) < (?, ?) ORDER BY `posts`.`created_at` DESC, `posts`.`id` DESC LIMIT 10", query)
+	assert.Equal(t, []interface{}{"active", 1, "2022-01-01", int64(42)}, values)
+
+	// previous page
+	query, values, err = newDB(conn).Table("posts").Limit(10).CursorPaginate(PrevCursor(42), "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`id` < ? ORDER BY `posts`.`id` DESC LIMIT 10", query)
+	assert.Equal(t, []interface{}{int64(42)}, values)
+
+	_, _, err = newDB(conn).Table("posts").CursorPaginate("", "-created_at", "id")
+	assert.ErrorIs(t, err, ErrMixedCursorDirection)
+	_, _, err = newDB(conn).Table("posts").CursorPaginate(NextCursor(1), "created_at", "id")
+	assert.ErrorIs(t, err, ErrCursorMismatch)
+	_, _, err = newDB(conn).Table("posts").CursorPaginate("%%%", "id")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestDB_CursorPaginateQualified(t *testing.T) {
+	query, values, err := newDB(conn).Table("users").InnerJoin("orders", "orders.user_id", "=", "users.id").Limit(10).
+		CursorPaginate(NextCursor(42), "users.id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` INNER JOIN `orders` ON `orders`.`user_id` = `users`.`id` WHERE `users`.`id` > ? ORDER BY `users`.`id` ASC LIMIT 10", query)
+	assert.Equal(t, []interface{}{int64(42)}, values)
+
+	query, _, err = newDB(conn).Table("users").Limit(10).CursorPaginate(NextCursor(1, 42), "-orders.created_at", "-users.id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE (`orders`.`created_at`, `users`.`id`) < (?, ?) ORDER BY `orders`.`created_at` DESC, `users`.`id` DESC LIMIT 10", query)
+}
+
+func TestDB_PaginateSQLServer(t *testing.T) {
+	mssql := &Connection{driver: "sqlserver"}
+	p, err := newDB(mssql).Table("posts").OrderBy("id", "DESC").Paginate(3, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [posts] ORDER BY [posts].[id] DESC OFFSET 40 ROWS FETCH NEXT 20 ROWS ONLY", p.Query)
+
+	p, err = newDB(mssql).Table("posts").Paginate(1, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [posts] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 20 ROWS ONLY", p.Query)
+
+	query, _, err := newDB(mssql).Table("posts").Limit(10).CursorPaginate(NextCursor(42), "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [posts] WHERE [posts].[id] > ? ORDER BY [posts].[id] ASC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", query)
+
+	query, values, err := newDB(mssql).Table("posts").Limit(10).CursorPaginate(NextCursor("2022-01-01", 42, 7), "-created_at", "-id", "-rank")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [posts] WHERE (([posts].[created_at] < ?) OR ([posts].[created_at] = ? AND [posts].[id] < ?)"+
+		" OR ([posts].[created_at] = ? AND [posts].[id] = ? AND [posts].[rank] < ?))"+
+		" ORDER BY [posts].[created_at] DESC, [posts].[id] DESC, [posts].[rank] DESC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", query)
+	assert.Equal(t, []interface{}{"2022-01-01", "2022-01-01", int64(42), "2022-01-01", int64(42), int64(7)}, values)
+}
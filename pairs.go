@@ -0,0 +1,98 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrPairsMismatch = errors.New("sql: columns paired with different amount of values")
+	ErrEmptyColumn   = errors.New("sql: column name is empty")
+)
+
+// Pair is a column and its value of inserted or updated row
+type Pair struct {
+	Column string
+	Value  interface{}
+}
+
+// Pairs is a row that keeps the exact column order, ex.:
+// Pairs{{"id", 1}, {"name", "John"}} or Cols("id", "name").Values(1, "John")
+type Pairs []Pair
+
+// Columns is an ordered list of columns to be paired with values
+type Columns []string
+
+// Cols starts an ordered row, ex.: Cols("id", "name").Values(1, "John")
+func Cols(cols ...string) Columns {
+	return cols
+}
+
+// pairsErr is the value of the pair standing for the row Values couldn't pair
+type pairsErr struct {
+	err error
+}
+
+// Values pairs the columns with values by position. If their amounts differ the row carries ErrPairsMismatch,
+// which is returned by Err and recorded by InsertPairs, UpdatePairs and UpdateRows
+func (c Columns) Values(values ...interface{}) Pairs {
+	if len(c) != len(values) {
+		return Pairs{{Value: pairsErr{fmt.Errorf("%w: %d columns, %d values", ErrPairsMismatch, len(c), len(values))}}}
+	}
+
+	row := make(Pairs, len(c))
+	for i, col := range c {
+		row[i] = Pair{Column: col, Value: values[i]}
+	}
+	return row
+}
+
+// Err returns the error of the rows built by Values, the row may be appended to other pairs
+func (p Pairs) Err() error {
+	for _, pair := range p {
+		if e, ok := pair.Value.(pairsErr); ok {
+			return e.err
+		}
+	}
+	return nil
+}
+
+// pairsOf converts map row to the pairs sorted by column
+func pairsOf(data map[string]interface{}) Pairs {
+	row := make(Pairs, 0, len(data))
+	for _, col := range sortedKeys(data) {
+		row = append(row, Pair{Column: col, Value: data[col]})
+	}
+	return row
+}
+
+// sortedKeys returns map keys in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// inStrings reports whether s is among ss
+func inStrings(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the value of the column
+func (p Pairs) get(col string) (interface{}, bool) {
+	for _, pair := range p {
+		if pair.Column == col {
+			return pair.Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,226 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidQueryParam is wrapped by the validation errors of ApplyQuery
+var ErrInvalidQueryParam = errors.New("sql: invalid query parameter")
+
+// QueryParams is the allow-list of the query string parameters ApplyQuery accepts
+type QueryParams struct {
+	// Filters are the columns filter[col] accepts along with their operators
+	// (the operators of WhereStruct tags), eq only if there are none
+	Filters map[string][]string
+	// Sorts are the columns sort accepts
+	Sorts []string
+	// Fields are the columns fields accepts
+	Fields []string
+	// DefaultPageSize is the page size if there is no page[size], MaxPageSize limits it,
+	// pages aren't limited if both are 0
+	DefaultPageSize int64
+	MaxPageSize     int64
+}
+
+// QueryParamError is the validation error of the query string parameter
+type QueryParamError struct {
+	Param  string
+	Reason string
+}
+
+func (e *QueryParamError) Error() string {
+	return fmt.Sprintf("%s %s: %s", ErrInvalidQueryParam, e.Param, e.Reason)
+}
+
+func (e *QueryParamError) Unwrap() error {
+	return ErrInvalidQueryParam
+}
+
+// QueryParamErrors are all validation errors of the query string
+type QueryParamErrors []*QueryParamError
+
+func (e QueryParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports errors.Is(err, ErrInvalidQueryParam)
+func (e QueryParamErrors) Is(target error) bool {
+	return target == ErrInvalidQueryParam
+}
+
+// ApplyQuery translates the query string of list endpoints to the query, ex.:
+//
+//	?filter[status]=active&filter[points][gte]=10&filter[id][in]=1,2&sort=-created_at,name&fields=id,name&page[size]=20&page[number]=2
+//
+// Filters are ANDed to WHERE clause as WhereMap does, in values are comma separated, between bounds as well,
+// null is true or false. Sort columns are ascending unless prefixed with "-". Pages are applied as LIMIT and OFFSET.
+// Columns and operators which aren't allowed by params are reported as QueryParamErrors,
+// the query is left intact if there are any. Other parameters are ignored
+func (r *DB) ApplyQuery(values url.Values, params QueryParams) error {
+	var errs QueryParamErrors
+	fail := func(param, format string, args ...interface{}) {
+		errs = append(errs, &QueryParamError{Param: param, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		filters          []filter
+		orders           []*orderBy
+		fields           []string
+		size, number     int64
+		hasSize, hasPage bool
+	)
+	for _, key := range keys {
+		if len(values[key]) != 1 {
+			fail(key, "must be given once")
+			continue
+		}
+		value := values[key][0]
+
+		switch {
+		case strings.HasPrefix(key, "filter["):
+			f, err := params.filter(key, value)
+			if err != nil {
+				fail(key, err.Error())
+				continue
+			}
+			filters = append(filters, f)
+		case key == "sort":
+			for _, col := range strings.Split(value, ",") {
+				o := &orderBy{Column: strings.TrimSpace(col), Direction: "ASC"}
+				if strings.HasPrefix(o.Column, "-") {
+					o.Column, o.Direction = o.Column[1:], "DESC"
+				}
+				if !inStrings(params.Sorts, o.Column) {
+					fail(key, "unknown column %q", o.Column)
+					continue
+				}
+				orders = append(orders, o)
+			}
+		case key == "fields":
+			for _, col := range strings.Split(value, ",") {
+				col = strings.TrimSpace(col)
+				if !inStrings(params.Fields, col) {
+					fail(key, "unknown column %q", col)
+					continue
+				}
+				fields = append(fields, col)
+			}
+		case key == "page[size]":
+			n, err := strconv.ParseInt(value, 10, 64)
+			switch {
+			case err != nil || n < 1:
+				fail(key, "must be a positive integer")
+			case params.MaxPageSize > 0 && n > params.MaxPageSize:
+				fail(key, "must not exceed %d", params.MaxPageSize)
+			default:
+				size, hasSize = n, true
+			}
+		case key == "page[number]":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 1 {
+				fail(key, "must be a positive integer")
+				continue
+			}
+			number, hasPage = n, true
+		case strings.HasPrefix(key, "page["):
+			fail(key, "unknown parameter")
+		}
+	}
+
+	if !hasSize {
+		size = params.DefaultPageSize
+		if size == 0 {
+			size = params.MaxPageSize
+		}
+	}
+	switch {
+	case hasPage && size == 0:
+		fail("page[number]", "needs page[size]")
+	case hasPage && number-1 > math.MaxInt64/size:
+		// the offset would overflow
+		fail("page[number]", "is too large")
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	r.whereFilters(filters)
+	for _, o := range orders {
+		r.OrderBy(o.Column, o.Direction)
+	}
+	if len(fields) > 0 {
+		r.Select(fields...)
+	}
+	if size > 0 {
+		r.Limit(size)
+		if number > 1 {
+			r.Offset((number - 1) * size)
+		}
+	}
+	return nil
+}
+
+// filter parses filter[col] or filter[col][op] parameter
+func (p QueryParams) filter(key, value string) (filter, error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+	if len(parts) > 2 || parts[0] == "" {
+		return filter{}, errors.New("must be filter[column] or filter[column][operator]")
+	}
+
+	col, op := parts[0], "eq"
+	if len(parts) == 2 {
+		op = strings.ToLower(parts[1])
+	}
+	if _, ok := filterOps[op]; !ok {
+		return filter{}, fmt.Errorf("unknown operator %q", op)
+	}
+	allowed, ok := p.Filters[col]
+	if !ok {
+		return filter{}, fmt.Errorf("unknown column %q", col)
+	}
+	if len(allowed) == 0 {
+		allowed = []string{"eq"}
+	}
+	if !inStrings(allowed, op) {
+		return filter{}, fmt.Errorf("operator %q isn't allowed", op)
+	}
+
+	f := filter{col: col, op: filterOps[op], opSet: true}
+	switch f.op {
+	case OpIn, OpNotIn, OpBetween, OpNotBetween:
+		list := strings.Split(value, ",")
+		for i := range list {
+			list[i] = strings.TrimSpace(list[i])
+		}
+		if (f.op == OpBetween || f.op == OpNotBetween) && len(list) != 2 {
+			return filter{}, errors.New("must be 2 comma separated values")
+		}
+		f.value = reflect.ValueOf(list)
+	case OpIsNull:
+		null, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter{}, errors.New("must be true or false")
+		}
+		f.value = reflect.ValueOf(null)
+	default:
+		f.value = reflect.ValueOf(value)
+	}
+	return f, nil
+}
@@ -0,0 +1,78 @@
+package buildsqlx
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var postParams = QueryParams{
+	Filters: map[string][]string{
+		"status":     nil,
+		"points":     {"gte", "lte"},
+		"id":         {"in"},
+		"deleted_at": {"null"},
+	},
+	Sorts:           []string{"created_at", "name"},
+	Fields:          []string{"id", "name"},
+	DefaultPageSize: 20,
+	MaxPageSize:     100,
+}
+
+func TestDB_ApplyQuery(t *testing.T) {
+	values, err := url.ParseQuery("filter[status]=active&filter[points][gte]=10&filter[id][in]=1,2&filter[deleted_at][null]=true" +
+		"&sort=-created_at,name&fields=id,name&page[size]=10&page[number]=3&include=author")
+	assert.NoError(t, err)
+
+	db := newDB(conn).Table("posts")
+	assert.NoError(t, db.ApplyQuery(values, postParams))
+	query, args := db.Query()
+	assert.Equal(t, "SELECT `id`, `name` FROM `posts` WHERE `posts`.`deleted_at` IS NULL AND `posts`.`id` IN (?, ?)"+
+		" AND `posts`.`points` >= ? AND `posts`.`status` = ? ORDER BY `posts`.`created_at` DESC, `posts`.`name` ASC LIMIT 10 OFFSET 20", query)
+	assert.Equal(t, []interface{}{"1", "2", "10", "active"}, args)
+
+	db = newDB(conn).Table("posts")
+	assert.NoError(t, db.ApplyQuery(url.Values{"filter[id][in]": {"1, 2 ,3"}}, postParams))
+	_, args = db.Query()
+	assert.Equal(t, []interface{}{"1", "2", "3"}, args)
+
+	db = newDB(conn).Table("posts")
+	assert.NoError(t, db.ApplyQuery(url.Values{}, postParams))
+	query, _ = db.Query()
+	assert.Equal(t, "SELECT * FROM `posts` LIMIT 20", query)
+}
+
+func TestDB_ApplyQueryErrors(t *testing.T) {
+	values, err := url.ParseQuery("filter[password]=x&filter[points][gt]=1&filter[deleted_at][null]=maybe" +
+		"&sort=secret&fields=id,password&page[size]=500&page[cursor]=abc")
+	assert.NoError(t, err)
+
+	db := newDB(conn).Table("posts")
+	err = db.ApplyQuery(values, postParams)
+	assert.ErrorIs(t, err, ErrInvalidQueryParam)
+
+	var errs QueryParamErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, QueryParamErrors{
+		{Param: "fields", Reason: `unknown column "password"`},
+		{Param: "filter[deleted_at][null]", Reason: "must be true or false"},
+		{Param: "filter[password]", Reason: `unknown column "password"`},
+		{Param: "filter[points][gt]", Reason: `operator "gt" isn't allowed`},
+		{Param: "page[cursor]", Reason: "unknown parameter"},
+		{Param: "page[size]", Reason: "must not exceed 100"},
+		{Param: "sort", Reason: `unknown column "secret"`},
+	}, errs)
+
+	// the query is left intact
+	query, _ := db.Query()
+	assert.Equal(t, "SELECT * FROM `posts`", query)
+
+	db = newDB(conn).Table("posts")
+	err = db.ApplyQuery(url.Values{"page[size]": {"100"}, "page[number]": {"92233720368547760"}}, postParams)
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, QueryParamErrors{{Param: "page[number]", Reason: "is too large"}}, errs)
+	query, _ = db.Query()
+	assert.Equal(t, "SELECT * FROM `posts`", query)
+}
@@ -0,0 +1,75 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrReturningUnsupported = errors.New("sql: returning rows is not supported")
+
+// SQL Server OUTPUT clause pseudo tables
+const (
+	outputInserted = "INSERTED"
+	outputDeleted  = "DELETED"
+)
+
+// Returning makes Insert, InsertBatch, InsertFrom, InsertIgnore, Upsert, Update, UpdateRows and Delete statements
+// return cols of the affected rows, use "*" for all columns. It renders RETURNING on PostgreSQL, SQLite and MariaDB (inserts and deletes only)
+// and OUTPUT INSERTED/DELETED on SQL Server, on other dialects the statement error is set, see Err
+func (r *DB) Returning(cols ...string) *DB {
+	r.Builder.returning = append(r.Builder.returning, cols...)
+	return r
+}
+
+// composeOutput writes SQL Server OUTPUT clause referring to pseudo table
+func (r *builder) composeOutput(pseudo string) {
+	if len(r.returning) == 0 || r.dialect != DialectSQLServer {
+		return
+	}
+
+	r.Pad().WriteString("OUTPUT").Pad()
+	for i, col := range r.returning {
+		if i > 0 {
+			r.Comma()
+		}
+
+		r.WriteString(pseudo).WriteByte('.')
+		if col == "*" {
+			r.WriteString(col)
+		} else {
+			r.Ident(col)
+		}
+	}
+}
+
+// composeReturning writes RETURNING clause of the statement verb (INSERT, UPDATE, DELETE),
+// or records an error if the dialect can't return rows from it
+func (r *builder) composeReturning(verb string) {
+	if len(r.returning) == 0 {
+		return
+	}
+
+	switch {
+	case r.dialect == DialectPostgres || r.dialect == DialectSQLite:
+	case r.dialect == DialectMariaDB && verb != "UPDATE":
+	case r.dialect == DialectSQLServer:
+		// rendered as OUTPUT clause
+		return
+	default:
+		r.setErr(fmt.Errorf("%w by %s %s statement", ErrReturningUnsupported, r.dialect, verb))
+		return
+	}
+
+	r.Pad().WriteString("RETURNING").Pad()
+	for i, col := range r.returning {
+		if i > 0 {
+			r.Comma()
+		}
+
+		if col == "*" {
+			r.WriteString(col)
+		} else {
+			r.Ident(col)
+		}
+	}
+}
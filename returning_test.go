@@ -0,0 +1,90 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Returning(t *testing.T) {
+	pg := func() *DB { return newDB(&Connection{driver: "postgres"}) }
+
+	query, _ := pg().Table("users").Returning("id").Insert(map[string]interface{}{"name": "John"})
+	assert.Equal(t, `INSERT INTO "users" ("name") VALUES (?) RETURNING "id"`, query)
+
+	query, _ = pg().Table("users").Where("id", OpEQ, 1).Returning("id", "name").Update(map[string]interface{}{"name": "John"})
+	assert.Equal(t, `UPDATE "users" SET "name" = ? WHERE "users"."id" = ? RETURNING "id", "name"`, query)
+
+	query, _ = pg().Table("users").Where("id", OpEQ, 1).Returning("*").Delete()
+	assert.Equal(t, `DELETE FROM "users" WHERE "users"."id" = ? RETURNING *`, query)
+
+	ms := func() *DB { return newDB(&Connection{driver: "sqlserver"}) }
+
+	query, _, err := ms().Table("users").Returning("id").InsertBatch([]map[string]interface{}{{"name": "John"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO [users] ([name]) OUTPUT INSERTED.[id] VALUES (?)`, query)
+
+	query, _ = ms().Table("users").Where("id", OpEQ, 1).Returning("*").Update(map[string]interface{}{"name": "John"})
+	assert.Equal(t, `UPDATE [users] SET [name] = ? OUTPUT INSERTED.* WHERE [users].[id] = ?`, query)
+
+	query, _ = ms().Table("users").Where("id", OpEQ, 1).Returning("id").Delete()
+	assert.Equal(t, `DELETE FROM [users] OUTPUT DELETED.[id] WHERE [users].[id] = ?`, query)
+
+	maria := newDB(&Connection{driver: "mariadb"})
+	query, _ = maria.Table("users").Returning("id").Insert(map[string]interface{}{"name": "John"})
+	assert.Equal(t, "INSERT INTO `users` (`name`) VALUES (?) RETURNING `id`", query)
+	assert.NoError(t, maria.Err())
+	maria.Table("users").Returning("id").Update(map[string]interface{}{"name": "John"})
+	assert.ErrorIs(t, maria.Err(), ErrReturningUnsupported)
+
+	my := newDB(&Connection{driver: "mysql"})
+	my.Table("users").Returning("id").Delete()
+	assert.ErrorIs(t, my.Err(), ErrReturningUnsupported)
+	// Table starts a new statement
+	my.Table("users").Delete()
+	assert.NoError(t, my.Err())
+}
+
+func TestDB_ReturningBatches(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "foo"}}
+	sub := func() *DB { return newDB(conn).Table("guests").Select("name") }
+
+	pg := func() *DB { return newDB(&Connection{driver: "postgres"}) }
+	query, _, err := pg().Table("users").Returning("id").InsertIgnoreBatch(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES (?, ?) ON CONFLICT DO NOTHING RETURNING "id"`, query)
+
+	db := pg().Table("users").Returning("id")
+	query, _ = db.InsertFrom([]string{"name"}, newDB(&Connection{driver: "postgres"}).Table("guests").Select("name"))
+	assert.Equal(t, `INSERT INTO "users" ("name") SELECT "name" FROM "guests" RETURNING "id"`, query)
+	assert.NoError(t, db.Err())
+
+	query, _, err = pg().Table("users").Returning("id").BatchTypes(map[string]string{"id": "bigint", "name": "text"}).
+		UpdateRows([]string{"id"}, rows, BatchValues)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name" = "v"."name" FROM (VALUES (CAST(? AS bigint), CAST(? AS text))) AS "v" ("id", "name")`+
+		` WHERE "users"."id" = "v"."id" RETURNING "id"`, query)
+
+	ms := func() *DB { return newDB(&Connection{driver: "sqlserver"}) }
+	db = ms().Table("users").Returning("id")
+	query, _ = db.InsertFrom(nil, newDB(&Connection{driver: "sqlserver"}).Table("guests"))
+	assert.Equal(t, `INSERT INTO [users] OUTPUT INSERTED.[id] SELECT * FROM [guests]`, query)
+	assert.NoError(t, db.Err())
+
+	query, _, err = ms().Table("users").Returning("id").UpdateRows([]string{"id"}, rows, BatchCase)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE [users] SET [name] = CASE WHEN [id] = ? THEN ? ELSE [name] END OUTPUT INSERTED.[id] WHERE [users].[id] IN (?)`, query)
+
+	my := func() *DB { return newDB(conn) }
+	_, _, err = my().Table("users").Returning("id").UpsertBatch(rows, []string{"id"}, []string{"name"})
+	assert.ErrorIs(t, err, ErrReturningUnsupported)
+	_, _, err = my().Table("users").Returning("id").InsertIgnoreBatch(rows)
+	assert.ErrorIs(t, err, ErrReturningUnsupported)
+	_, _, err = my().Table("users").Returning("id").UpdateRows([]string{"id"}, rows, BatchCase)
+	assert.ErrorIs(t, err, ErrReturningUnsupported)
+	_, _, err = my().Table("users").Returning("id").UpdateRows([]string{"id"}, rows, BatchUpsert)
+	assert.ErrorIs(t, err, ErrReturningUnsupported)
+	db = my().Table("users").Returning("id")
+	db.InsertFrom(nil, sub())
+	assert.ErrorIs(t, db.Err(), ErrReturningUnsupported)
+}
@@ -2,14 +2,16 @@ package buildsqlx
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
-
-	"github.com/spf13/cast"
 )
 
+// Errors returned by the schema builders
 var (
-	errTableOnlySupportOneAutoIncrements = errors.New("sql: the table only support one increments column")
+	ErrMultipleIncrements       = errors.New("sql: the table only support one increments column")
+	ErrNoColumn                 = errors.New("sql: there is no column to apply the modifier to")
+	ErrInvalidReferentialAction = errors.New("sql: invalid foreign key referential action")
 )
 
 // mysql column types
@@ -47,6 +49,9 @@ const (
 	Rename     = " RENAME "
 )
 
+// referential actions of foreign keys
+var referentialActions = []string{"CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT", "NO ACTION"}
+
 type colType string
 
 // Table is the type for operations on table schema
@@ -55,6 +60,8 @@ type Table struct {
 	tblName string
 	comment *string
 	sb      *schemaBuilder
+	dialect string
+	err     error
 }
 
 // collection of properties for the column
@@ -81,11 +88,14 @@ type column struct {
 
 // CreateTable creates and/or manipulates table structure with an appropriate types/indices/comments/defaults/nulls etc
 func (r *DB) CreateTable(tblName string, fn func(table *Table) error) (sql []string, err error) {
-	tbl := &Table{tblName: tblName, sb: newSchemaBuilder()}
+	tbl := &Table{tblName: tblName, sb: newSchemaBuilder(), dialect: r.Builder.dialect}
 	err = fn(tbl) // run fn with Table struct passed to collect columns to []*column slice
 	if err != nil {
 		return nil, err
 	}
+	if tbl.err != nil {
+		return nil, tbl.err
+	}
 
 	l := len(tbl.columns)
 	if l > 0 {
@@ -98,11 +108,14 @@ func (r *DB) CreateTable(tblName string, fn func(table *Table) error) (sql []str
 
 // ModifyTable creates and/or manipulates table structure with an appropriate types/indices/comments/defaults/nulls etc
 func (r *DB) ModifyTable(tblName string, fn func(table *Table) error) (sql []string, err error) {
-	tbl := &Table{tblName: tblName, sb: newSchemaBuilder()}
+	tbl := &Table{tblName: tblName, sb: newSchemaBuilder(), dialect: r.Builder.dialect}
 	err = fn(tbl) // run fn with Table struct passed to collect columns to []*column slice
 	if err != nil {
 		return nil, err
 	}
+	if tbl.err != nil {
+		return nil, tbl.err
+	}
 
 	l := len(tbl.columns)
 	if l > 0 {
@@ -244,35 +257,57 @@ func (t *Table) Json(colNm string) *Table {
 	return t
 }
 
+// last returns the column modifiers are applied to, a detached one is returned
+// and the error is recorded if no column was added yet
+func (t *Table) last() *column {
+	if len(t.columns) == 0 {
+		t.setErr(ErrNoColumn)
+		return &column{}
+	}
+	return t.columns[len(t.columns)-1]
+}
+
+// setErr records the error unless there is one already
+func (t *Table) setErr(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
 // NotNull sets the last column to not null
 func (t *Table) NotNull() *Table {
 	isNotNull := true
-	t.columns[len(t.columns)-1].IsNotNull = &isNotNull
+	t.last().IsNotNull = &isNotNull
 	return t
 }
 
 // Collation sets the last column to specified collation
 func (t *Table) Collation(coll string) *Table {
-	t.columns[len(t.columns)-1].Collation = &coll
+	t.last().Collation = &coll
 	return t
 }
 
 // Collation sets the last column to specified collation
 func (t *Table) After(coll string) *Table {
-	t.columns[len(t.columns)-1].After = &coll
+	t.last().After = &coll
 	return t
 }
 
-// Default sets the default column value
+// Default sets the default column value, strings, numbers, bools and time are rendered as literals
+// of their type, use Raw for expressions, ex.: Default(Raw(CurrentTimestamp))
 func (t *Table) Default(val interface{}) *Table {
-	v := cast.ToString(val)
-	t.columns[len(t.columns)-1].Default = &v
+	v, err := literal(t.dialect, val)
+	if err != nil {
+		t.setErr(err)
+		return t
+	}
+	t.last().Default = &v
 	return t
 }
 
 // Comment sets the column comment
 func (t *Table) Comment(cmt string) *Table {
-	t.columns[len(t.columns)-1].Comment = &cmt
+	t.last().Comment = &cmt
 	return t
 }
 
@@ -283,56 +318,51 @@ func (t *Table) TableComment(cmt string) {
 
 // Index sets the last column to btree index
 func (t *Table) Index(idxName string) *Table {
-	t.columns[len(t.columns)-1].IdxName = idxName
-	t.columns[len(t.columns)-1].IsIndex = true
+	col := t.last()
+	col.IdxName = idxName
+	col.IsIndex = true
 	return t
 }
 
 // Unique sets the last column to unique index
 func (t *Table) Unique(idxName string) *Table {
-	t.columns[len(t.columns)-1].IdxName = idxName
-	t.columns[len(t.columns)-1].IsUnique = true
+	col := t.last()
+	col.IdxName = idxName
+	col.IsUnique = true
 	return t
 }
 
-// ForeignKey sets the last column to reference rfcTbl on onCol with idxName foreign key index
+// ForeignKey sets the last column to reference rfcTbl on onCol with idxName foreign key index,
+// update and delete are referential actions: CASCADE, SET NULL, SET DEFAULT, RESTRICT or NO ACTION (the default)
 func (t *Table) ForeignKey(idxName, rfcTbl, onCol string, update, delete *string) *Table {
+	col := t.last()
+
+	query := &schemaBuilder{}
+	query.WriteString("CONSTRAINT").Pad().Ident(idxName).
+		Pad().WriteString("FOREIGN KEY").Pad().WriteString("(").Ident(col.Name).WriteString(")").
+		Pad().WriteString("REFERENCES").Pad().Ident(rfcTbl).
+		Pad().WriteString("(").Ident(onCol).WriteString(")")
+
+	for _, action := range []struct {
+		event string
+		value *string
+	}{{"UPDATE", update}, {"DELETE", delete}} {
+		query.Pad().WriteString("ON").Pad().WriteString(action.event).Pad()
+		if action.value == nil {
+			query.WriteString("NO ACTION")
+			continue
+		}
 
-	query := strings.Builder{}
-	query.WriteString(" CONSTRAINT ")
-	query.WriteByte('`')
-	query.WriteString(idxName)
-	query.WriteByte('`')
-	query.WriteString(" FOREIGN KEY ( ")
-	query.WriteByte('`')
-	query.WriteString(t.columns[len(t.columns)-1].Name)
-	query.WriteByte('`')
-	query.WriteString(") REFERENCES ")
-	query.WriteByte('`')
-	query.WriteString(rfcTbl)
-	query.WriteByte('`')
-	query.WriteString(" (")
-	query.WriteByte('`')
-	query.WriteString(onCol)
-	query.WriteByte('`')
-	query.WriteString(")")
-
-	if update != nil {
-		query.WriteString(" ON UPDATE ")
-		query.WriteString(*update)
-	} else {
-		query.WriteString(" ON UPDATE NO ACTION ")
-	}
-
-	if delete != nil {
-		query.WriteString(" ON DELETE ")
-		query.WriteString(*delete)
-	} else {
-		query.WriteString(" ON DELETE NO ACTION ")
+		v := strings.ToUpper(strings.TrimSpace(*action.value))
+		if !inStrings(referentialActions, v) {
+			t.setErr(fmt.Errorf("%w: %q", ErrInvalidReferentialAction, *action.value))
+			return t
+		}
+		query.WriteString(v)
 	}
 
 	key := query.String()
-	t.columns[len(t.columns)-1].ForeignKey = &key
+	col.ForeignKey = &key
 	return t
 }
 
@@ -351,7 +381,7 @@ func buildDateTIme(colNm, t, defType string, isDefault bool) *column {
 
 // Change the column type/length/nullable etc options
 func (t *Table) Change() {
-	t.columns[len(t.columns)-1].IsModify = true
+	t.last().IsModify = true
 }
 
 // Rename the column "from" to the "to"
@@ -376,7 +406,7 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 	autoIncr := 0
 
 	t.sb.WriteString("CREATE TABLE")
-	t.sb.Pad().Ident(t.tblName)
+	t.sb.Pad().Ident(t.tblName).Pad()
 	t.sb.Nested(func(sb *schemaBuilder) {
 
 		for k, col := range t.columns {
@@ -390,11 +420,11 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 			}
 			// 字符集
 			if col.ChartSet != nil {
-				sb.Pad().WriteString("CHARACTER SET").Pad().WriteByte('\'').WriteString(*col.ChartSet).WriteByte('\'')
+				sb.Pad().WriteString("CHARACTER SET").Pad().WriteString(quoteString(t.dialect, *col.ChartSet))
 			}
 			// Collation
 			if col.Collation != nil {
-				sb.Pad().WriteString("COLLATE").Pad().WriteByte('\'').WriteString(*col.Collation).WriteByte('\'')
+				sb.Pad().WriteString("COLLATE").Pad().WriteString(quoteString(t.dialect, *col.Collation))
 			}
 			// 不为空
 			if col.IsNotNull != nil {
@@ -406,26 +436,16 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 			}
 			// 默认值
 			if col.Default != nil {
-
-				if strings.HasPrefix(string(col.ColumnType), TypeChar) {
-					sb.Pad().WriteString("DEFAULT").Pad().WriteByte('\'').WriteString(*col.Default).WriteByte('\'')
-
-				} else if strings.HasPrefix(string(col.ColumnType), TypeVarchar) {
-					sb.Pad().WriteString("DEFAULT").Pad().WriteByte('\'').WriteString(*col.Default).WriteByte('\'')
-				} else {
-					switch colType(col.ColumnType) {
-					case TypeDate, TypeTime, TypeDateTime:
-						sb.Pad().WriteString("DEFAULT").Pad().WriteByte('\'').WriteString(*col.Default).WriteByte('\'')
-					case TypeBlob, TypeLongBlob, TypeText, TypeLongText, TypeJson:
-						// do nothing
-					default:
-						sb.Pad().WriteString("DEFAULT").Pad().WriteString(*col.Default)
-					}
+				switch colType(col.ColumnType) {
+				case TypeBlob, TypeLongBlob, TypeText, TypeLongText, TypeJson:
+					// do nothing
+				default:
+					sb.Pad().WriteString("DEFAULT").Pad().WriteString(*col.Default)
 				}
 			}
 			// 备注
 			if col.Comment != nil {
-				sb.Pad().WriteString("COMMENT").Pad().WriteByte('\'').WriteString(*col.Comment).WriteByte('\'')
+				sb.Pad().WriteString("COMMENT").Pad().WriteString(quoteString(t.dialect, *col.Comment))
 			}
 
 			if k < l-1 {
@@ -434,14 +454,14 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 
 			// 主键
 			if col.IsPrimaryKey {
-				sb.child.Comma().WriteString("PRIMARY KEY").Nested(func(csb *schemaBuilder) {
+				sb.child.Comma().WriteString("PRIMARY KEY").Pad().Nested(func(csb *schemaBuilder) {
 					csb.Ident(col.Name)
 				})
 			}
 
 			// 索引
 			if col.IsIndex {
-				sb.child.Comma().Pad().WriteString("INDEX").Pad().Ident(col.IdxName).Pad().Nested(func(csb *schemaBuilder) {
+				sb.child.Comma().WriteString("INDEX").Pad().Ident(col.IdxName).Pad().Nested(func(csb *schemaBuilder) {
 					csb.Ident(col.Name)
 					csb.Pad()
 					csb.WriteString("ASC")
@@ -449,7 +469,7 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 			}
 			// 唯一索引
 			if col.IsUnique {
-				sb.child.Comma().Pad().WriteString("UNIQUE INDEX").Pad().Ident(col.IdxName).Pad().Nested(func(csb *schemaBuilder) {
+				sb.child.Comma().WriteString("UNIQUE INDEX").Pad().Ident(col.IdxName).Pad().Nested(func(csb *schemaBuilder) {
 					csb.Ident(col.Name)
 					csb.Pad()
 					csb.WriteString("ASC")
@@ -465,11 +485,11 @@ func (r *DB) createTable(t *Table) (sql []string, err error) {
 	})
 
 	if autoIncr > 1 {
-		return nil, errTableOnlySupportOneAutoIncrements
+		return nil, ErrMultipleIncrements
 	}
 
 	if t.comment != nil {
-		t.sb.WriteString("COMMENT").Pad().WriteByte('\'').WriteString(*t.comment).WriteByte('\'')
+		t.sb.Pad().WriteString("COMMENT").Pad().WriteString(quoteString(t.dialect, *t.comment))
 	}
 
 	sql = append(sql, t.sb.String())
@@ -508,11 +528,11 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			}
 			// 字符集
 			if col.ChartSet != nil {
-				t.sb.Pad().WriteString("CHARACTER SET").Pad().WriteByte('\'').WriteString(*col.ChartSet).WriteByte('\'')
+				t.sb.Pad().WriteString("CHARACTER SET").Pad().WriteString(quoteString(t.dialect, *col.ChartSet))
 			}
 			// Collation
 			if col.Collation != nil {
-				t.sb.Pad().WriteString("COLLATE").Pad().WriteByte('\'').WriteString(*col.Collation).WriteByte('\'')
+				t.sb.Pad().WriteString("COLLATE").Pad().WriteString(quoteString(t.dialect, *col.Collation))
 			}
 			// 不为空
 			if col.IsNotNull != nil {
@@ -525,8 +545,6 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			// 默认值
 			if col.Default != nil {
 				switch colType(col.ColumnType) {
-				case TypeChar, TypeVarchar, TypeDate, TypeTime, TypeDateTime:
-					t.sb.Pad().WriteString("DEFAULT").Pad().WriteByte('\'').WriteString(*col.Default).WriteByte('\'')
 				case TypeBlob, TypeLongBlob, TypeText, TypeLongText, TypeJson:
 					// do nothing
 				default:
@@ -535,7 +553,7 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			}
 			// 备注
 			if col.Comment != nil {
-				t.sb.Pad().WriteString("COMMENT").Pad().WriteByte('\'').WriteString(*col.Comment).WriteByte('\'')
+				t.sb.Pad().WriteString("COMMENT").Pad().WriteString(quoteString(t.dialect, *col.Comment))
 			}
 		} else {
 			// 添加字段
@@ -548,11 +566,11 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			}
 			// 字符集
 			if col.ChartSet != nil {
-				t.sb.Pad().WriteString("CHARACTER SET").Pad().WriteByte('\'').WriteString(*col.ChartSet).WriteByte('\'')
+				t.sb.Pad().WriteString("CHARACTER SET").Pad().WriteString(quoteString(t.dialect, *col.ChartSet))
 			}
 			// Collation
 			if col.Collation != nil {
-				t.sb.Pad().WriteString("COLLATE").Pad().WriteByte('\'').WriteString(*col.Collation).WriteByte('\'')
+				t.sb.Pad().WriteString("COLLATE").Pad().WriteString(quoteString(t.dialect, *col.Collation))
 			}
 			// 不为空
 			if col.IsNotNull != nil {
@@ -565,8 +583,6 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			// 默认值
 			if col.Default != nil {
 				switch colType(col.ColumnType) {
-				case TypeChar, TypeVarchar, TypeDate, TypeTime, TypeDateTime:
-					t.sb.Pad().WriteString("DEFAULT").Pad().WriteByte('\'').WriteString(*col.Default).WriteByte('\'')
 				case TypeBlob, TypeLongBlob, TypeText, TypeLongText, TypeJson:
 					// do nothing
 				default:
@@ -575,7 +591,7 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			}
 			// 备注
 			if col.Comment != nil {
-				t.sb.Pad().WriteString("COMMENT").Pad().WriteByte('\'').WriteString(*col.Comment).WriteByte('\'')
+				t.sb.Pad().WriteString("COMMENT").Pad().WriteString(quoteString(t.dialect, *col.Comment))
 			}
 			// After,默认添加到after之后
 			if col.After != nil {
@@ -593,7 +609,7 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			// 索引
 			if col.IsIndex {
 				t.sb.child.Comma().
-					Pad().WriteString("ADD INDEX").
+					WriteString("ADD INDEX").
 					Pad().Ident(col.IdxName).Pad().
 					Nested(func(csb *schemaBuilder) {
 						csb.Ident(col.Name)
@@ -604,7 +620,7 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 			// 唯一索引
 			if col.IsUnique {
 				t.sb.child.Comma().
-					Pad().WriteString("ADD UNIQUE INDEX").
+					WriteString("ADD UNIQUE INDEX").
 					Pad().Ident(col.IdxName).Pad().
 					Nested(func(csb *schemaBuilder) {
 						csb.Ident(col.Name)
@@ -622,7 +638,7 @@ func (r *DB) modifyTable(t *Table) (sql []string, err error) {
 	t.sb.WriteString(t.sb.child.String())
 
 	if t.comment != nil {
-		t.sb.Comma().Pad().WriteString("COMMENT").Pad().WriteByte('\'').WriteString(*t.comment).WriteByte('\'')
+		t.sb.Comma().WriteString("COMMENT").Pad().WriteString(quoteString(t.dialect, *t.comment))
 	}
 
 	sql = append(sql, t.sb.String())
@@ -49,10 +49,9 @@ func (b *schemaBuilder) IdentPoint(str string) *schemaBuilder {
 	return b
 }
 
-// Ident adds a comma to the query.
+// Quote quotes the identifier escaping the quotes inside it.
 func (b *schemaBuilder) Quote(ident string) string {
-	quote := "`"
-	return quote + ident + quote
+	return quoteIdent(DialectMySQL, ident)
 }
 
 // Comma adds a comma to the query.
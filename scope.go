@@ -0,0 +1,130 @@
+package buildsqlx
+
+import "strings"
+
+// globalScope is the named scope applied to every query on the table
+type globalScope struct {
+	name  string
+	scope func(q *DB) *DB
+}
+
+// When extends the query with fn if cond is true, ex.:
+//
+//	db.Table("posts").When(status != "", func(q *DB) {
+//		q.Where("status", buildsqlx.OpEQ, status)
+//	})
+func (r *DB) When(cond bool, fn func(q *DB)) *DB {
+	if cond {
+		fn(r)
+	}
+	return r
+}
+
+// Unless extends the query with fn if cond is false
+func (r *DB) Unless(cond bool, fn func(q *DB)) *DB {
+	return r.When(!cond, fn)
+}
+
+// Scope applies reusable parts of queries in order, ex.:
+//
+//	published := func(q *DB) *DB { return q.Where("published", buildsqlx.OpEQ, true) }
+//	db.Table("posts").Scope(published, byAuthor(id)).Query()
+func (r *DB) Scope(scopes ...func(q *DB) *DB) *DB {
+	for _, scope := range scopes {
+		r = scope(r)
+	}
+	return r
+}
+
+// AddGlobalScope registers the scope applied to every select, update and delete stmt on the table,
+// e.g. the tenant filter or deleted_at IS NULL. Registering a scope with the same name replaces it.
+// Conditions of the scope are ANDed to the query ones with parentheses around both, so that ORs
+// of the query can't bypass the scope
+func (c *Connection) AddGlobalScope(table, name string, scope func(q *DB) *DB) *Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scopes == nil {
+		c.scopes = make(map[string][]globalScope)
+	}
+	for i, s := range c.scopes[table] {
+		if s.name == name {
+			c.scopes[table][i].scope = scope
+			return c
+		}
+	}
+	c.scopes[table] = append(c.scopes[table], globalScope{name: name, scope: scope})
+	return c
+}
+
+// globalScopes returns the scopes of the table
+func (c *Connection) globalScopes(table string) []globalScope {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]globalScope(nil), c.scopes[table]...)
+}
+
+// WithoutGlobalScope disables the global scopes by name for the query
+func (r *DB) WithoutGlobalScope(names ...string) *DB {
+	without := make(map[string]struct{}, len(r.Builder.withoutScopes)+len(names))
+	for name := range r.Builder.withoutScopes {
+		without[name] = struct{}{}
+	}
+	for _, name := range names {
+		without[name] = struct{}{}
+	}
+	r.Builder.withoutScopes = without
+	return r
+}
+
+// WithoutGlobalScopes disables all global scopes for the query
+func (r *DB) WithoutGlobalScopes() *DB {
+	r.Builder.noScopes = true
+	return r
+}
+
+// applyScopes ANDs the global scopes of the table to WHERE clause once per stmt,
+// every scope and the query conditions are wrapped with parentheses
+func (r *DB) applyScopes() {
+	builder := r.Builder
+	if builder.scoped || builder.noScopes || r.Conn == nil {
+		return
+	}
+	builder.scoped = true
+
+	own := builder.where
+	var parts []*sqlBuilder
+	for _, s := range r.Conn.globalScopes(builder.table) {
+		if _, ok := builder.withoutScopes[s.name]; ok {
+			continue
+		}
+
+		builder.where = own.derive()
+		s.scope(r)
+		if builder.where.Len() > 0 || builder.where.err != nil {
+			parts = append(parts, builder.where)
+		}
+	}
+	builder.where = own
+	if len(parts) == 0 {
+		return
+	}
+	if own.Len() > 0 {
+		parts = append(parts, own)
+	}
+
+	w := own.derive()
+	w.WriteString(where)
+	for i, part := range parts {
+		if i > 0 {
+			w.WriteString(and)
+		}
+		w.WriteByte('(').WriteString(strings.TrimPrefix(part.String(), where)).WriteByte(')')
+		w.args = append(w.args, part.args...)
+		if part.err != nil {
+			w.setErr(part.err)
+		}
+	}
+	builder.where = w
+}
@@ -0,0 +1,77 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_WhenUnless(t *testing.T) {
+	status, author := "draft", 0
+	query, values := newDB(conn).Table("posts").
+		When(status != "", func(q *DB) {
+			q.Where("status", OpEQ, status)
+		}).
+		When(author > 0, func(q *DB) {
+			q.Where("author_id", OpEQ, author)
+		}).
+		Unless(author > 0, func(q *DB) {
+			q.WhereNull("author_id")
+		}).Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`status` = ? AND `posts`.`author_id` IS NULL", query)
+	assert.Equal(t, []interface{}{"draft"}, values)
+}
+
+func TestDB_Scope(t *testing.T) {
+	published := func(q *DB) *DB { return q.Where("published", OpEQ, true) }
+	byAuthor := func(id int) func(q *DB) *DB {
+		return func(q *DB) *DB { return q.Where("author_id", OpEQ, id) }
+	}
+
+	query, values := newDB(conn).Table("posts").Scope(published, byAuthor(7)).OrderBy("id", "desc").Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`published` = ? AND `posts`.`author_id` = ? ORDER BY `posts`.`id` DESC", query)
+	assert.Equal(t, []interface{}{true, 7}, values)
+}
+
+func TestDB_GlobalScopes(t *testing.T) {
+	c := &Connection{driver: "mysql"}
+	c.AddGlobalScope("posts", "tenant", func(q *DB) *DB {
+		return q.Where("tenant_id", OpEQ, 3)
+	}).AddGlobalScope("posts", "soft_delete", func(q *DB) *DB {
+		return q.WhereNull("deleted_at")
+	})
+
+	query, values := newDB(c).Table("posts").Where("id", OpEQ, 1).OrWhere("slug", OpEQ, "foo").Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE (`posts`.`tenant_id` = ?) AND (`posts`.`deleted_at` IS NULL)"+
+		" AND (`posts`.`id` = ? OR `posts`.`slug` = ?)", query)
+	assert.Equal(t, []interface{}{3, 1, "foo"}, values)
+
+	query, values = newDB(c).Table("posts").WithoutGlobalScope("soft_delete").Delete()
+	assert.Equal(t, "DELETE FROM `posts` WHERE (`posts`.`tenant_id` = ?)", query)
+	assert.Equal(t, []interface{}{3}, values)
+
+	query, values = newDB(c).Table("posts").WithoutGlobalScopes().Update(map[string]interface{}{"title": "foo"})
+	assert.Equal(t, "UPDATE `posts` SET `title` = ?", query)
+	assert.Equal(t, []interface{}{"foo"}, values)
+
+	// other tables aren't scoped
+	query, _ = newDB(c).Table("users").Count()
+	assert.Equal(t, "SELECT COUNT(*) FROM `users`", query)
+
+	// forks render the scopes once
+	db := newDB(c).Table("posts")
+	page, err := db.Paginate(2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM `posts` WHERE (`posts`.`tenant_id` = ?) AND (`posts`.`deleted_at` IS NULL)", page.CountQuery)
+	assert.Equal(t, "SELECT * FROM `posts` WHERE (`posts`.`tenant_id` = ?) AND (`posts`.`deleted_at` IS NULL) LIMIT 10 OFFSET 10", page.Query)
+}
+
+func TestDB_WhereChaining(t *testing.T) {
+	query, values := newDB(conn).Table("posts").Where("id", OpGT, 1).WhereRaw("LENGTH(title) > ?", 3).
+		WhereIn("status", "a", "b").Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`id` > ? AND LENGTH(title) > ? AND `posts`.`status` IN (?, ?)", query)
+	assert.Equal(t, []interface{}{1, 3, "a", "b"}, values)
+
+	query, _ = newDB(conn).Table("posts").OrWhere("id", OpEQ, 1).Query()
+	assert.Equal(t, "SELECT * FROM `posts` WHERE `posts`.`id` = ?", query)
+}
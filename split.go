@@ -0,0 +1,301 @@
+package buildsqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTooManyColumns     = errors.New("sql: row has more columns than statement params limit")
+	ErrStatementTooLarge  = errors.New("sql: single row exceeds statement bytes budget")
+	ErrEmptyWhereInValues = errors.New("sql: there are no values for IN list")
+	ErrSplitUnsupported   = errors.New("sql: query can't be split without changing its result")
+)
+
+// aggregateCall matches aggregate function calls of raw selections
+var aggregateCall = regexp.MustCompile(`(?i)\b(COUNT|SUM|AVG|MIN|MAX|GROUP_CONCAT|STRING_AGG|ARRAY_AGG)\s*\(`)
+
+// default placeholders limits per statement
+const (
+	maxParamsMySQL     = 65535
+	maxParamsPostgres  = 65535
+	maxParamsSQLite    = 999 // SQLITE_MAX_VARIABLE_NUMBER before 3.32.0, 32766 since
+	maxParamsSQLServer = 2100
+)
+
+// SplitOptions limits the size of every statement produced by SplitInsertBatch and SplitWhereIn
+type SplitOptions struct {
+	// MaxParams is the maximum amount of placeholders per statement, the dialect limit by default
+	MaxParams int
+	// MaxBytes is an estimated size budget per statement (e.g. max_allowed_packet), unlimited by default
+	MaxBytes int
+	// TempTable names the temporary table the IN list is loaded into and joined with,
+	// instead of splitting the select, when there are more than TempTableAfter values
+	TempTable      string
+	TempTableAfter int
+}
+
+// maxParams returns the placeholders limit for the dialect unless it's set explicitly
+func (o SplitOptions) maxParams(dialect string) int {
+	if o.MaxParams > 0 {
+		return o.MaxParams
+	}
+
+	switch dialect {
+	case DialectPostgres:
+		return maxParamsPostgres
+	case DialectSQLite:
+		return maxParamsSQLite
+	case DialectSQLServer:
+		return maxParamsSQLServer
+	default:
+		return maxParamsMySQL
+	}
+}
+
+// SplitInsertBatch splits InsertBatch into as many statements as needed to keep each of them
+// within params limit and bytes budget
+func (r *DB) SplitInsertBatch(data []map[string]interface{}, opts SplitOptions, columns ...string) ([]Statement, error) {
+	builder := r.Builder
+	if builder.table == "" {
+		return nil, ErrNoTable
+	}
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	columns, err := prepareInsertBatch(data, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	perStmt := opts.maxParams(builder.dialect) / len(columns)
+	if perStmt < 1 {
+		return nil, ErrTooManyColumns
+	}
+
+	// the size of a statement is its header plus all the rows
+	header := builder.fork()
+	header.writeInsertBatch(insertInto, columns, nil)
+	headerSize := header.Len()
+
+	var stmts []Statement
+	flush := func(rows []map[string]interface{}) {
+		b := builder.fork()
+		values := b.writeInsertBatch(insertInto, columns, rows)
+		stmts = append(stmts, r.statement(KindInsert, b.String(), values, nil))
+	}
+
+	from, size := 0, headerSize
+	for i, row := range data {
+		rowSize := 2 + 3*len(columns)
+		for _, v := range row {
+			rowSize += argSize(v)
+		}
+
+		if opts.MaxBytes > 0 && headerSize+rowSize > opts.MaxBytes {
+			return nil, ErrStatementTooLarge
+		}
+
+		if i-from == perStmt || (opts.MaxBytes > 0 && size+rowSize > opts.MaxBytes) {
+			flush(data[from:i])
+			from, size = i, headerSize
+		}
+		size += rowSize
+	}
+	flush(data[from:])
+
+	return stmts, nil
+}
+
+// SplitWhereIn adds col IN (in...) condition to the query and splits it into as many select statements
+// as needed to keep each of them within params limit and bytes budget.
+// With TempTable set long lists are loaded to the temporary table and joined instead, the statements are:
+// create temporary table, insert values batches, select joined with the table, drop the table.
+// Queries which are limited, grouped, distinct, aggregated or unioned return ErrSplitUnsupported
+// unless the list is loaded to the temporary table, as every split statement would compute its own result
+func (r *DB) SplitWhereIn(col string, in []interface{}, opts SplitOptions) ([]Statement, error) {
+	builder := r.Builder
+	if builder.table == "" {
+		return nil, ErrNoTable
+	}
+	builder.allow(col)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if len(in) == 0 {
+		return nil, ErrEmptyWhereInValues
+	}
+
+	if opts.TempTable != "" && len(in) > opts.TempTableAfter {
+		return r.whereInTempTable(col, in, opts)
+	}
+	if err := builder.splittable(); err != nil {
+		return nil, err
+	}
+
+	// params and size taken by the rest of the query
+	base := &DB{Builder: builder.fork(), Conn: r.Conn}
+	query, values := base.Query()
+	perStmt := opts.maxParams(builder.dialect) - len(values)
+	if perStmt < 1 {
+		return nil, ErrTooManyColumns
+	}
+	baseSize := len(query) + len(col) + len(builder.table) + 16
+	for _, v := range values {
+		baseSize += argSize(v)
+	}
+
+	var stmts []Statement
+	flush := func(chunk []interface{}) {
+		q := &DB{Builder: builder.fork(), Conn: r.Conn}
+		q.Builder.andWhereGroup(func(s *sqlBuilder) {
+			s.Column(builder.table, col).WriteOp(OpIn).Nested(func(s *sqlBuilder) {
+				s.Args(chunk...)
+			})
+		})
+		stmts = append(stmts, q.QueryStmt())
+	}
+
+	from, size := 0, baseSize
+	for i, v := range in {
+		vSize := 3 + argSize(v)
+		if opts.MaxBytes > 0 && baseSize+vSize > opts.MaxBytes {
+			return nil, ErrStatementTooLarge
+		}
+
+		if i-from == perStmt || (opts.MaxBytes > 0 && size+vSize > opts.MaxBytes) {
+			flush(in[from:i])
+			from, size = i, baseSize
+		}
+		size += vSize
+	}
+	flush(in[from:])
+
+	return stmts, nil
+}
+
+// whereInTempTable loads IN list to the temporary table and joins the query with it
+func (r *DB) whereInTempTable(col string, in []interface{}, opts SplitOptions) ([]Statement, error) {
+	builder := r.Builder
+	tmp := opts.TempTable
+	create, drop := "CREATE TEMPORARY TABLE", "DROP TABLE"
+	switch builder.dialect {
+	case DialectMySQL, DialectMariaDB:
+		drop = "DROP TEMPORARY TABLE"
+	case DialectSQLite:
+		create = "CREATE TEMP TABLE"
+	case DialectSQLServer:
+		create = "CREATE TABLE"
+		if !strings.HasPrefix(tmp, "#") {
+			tmp = "#" + tmp
+		}
+	}
+
+	stmts := []Statement{r.ddlStatement(tmp, create+" "+builder.Quote(tmp)+" ("+builder.Quote("v")+" "+tempColumnType(in[0])+")")}
+
+	// the rows are joined with the table, so duplicate values would select the matching rows more than once
+	in = uniqueValues(in)
+	rows := make([]map[string]interface{}, len(in))
+	for i, v := range in {
+		rows[i] = map[string]interface{}{"v": v}
+	}
+	load := &DB{Builder: builder.fork(), Conn: r.Conn}
+	load.Builder.table = tmp
+	inserts, err := load.SplitInsertBatch(rows, SplitOptions{MaxParams: opts.MaxParams, MaxBytes: opts.MaxBytes})
+	if err != nil {
+		return nil, err
+	}
+	stmts = append(stmts, inserts...)
+
+	q := &DB{Builder: builder.fork(), Conn: r.Conn}
+	on := q.Builder.derive()
+	on.Column(builder.table, col).WriteOp(OpEQ).IdentPoint(tmp).Ident("v")
+	q.buildJoin(joinInner, q.Builder.Quote(tmp), on.String())
+	stmts = append(stmts, q.QueryStmt(), r.ddlStatement(tmp, drop+" "+builder.Quote(tmp)))
+
+	return stmts, nil
+}
+
+// splittable reports ErrSplitUnsupported if splitting the query by IN list would change its result
+func (r *builder) splittable() error {
+	switch {
+	case r.limit > 0 || r.offset > 0:
+		return fmt.Errorf("%w: LIMIT or OFFSET", ErrSplitUnsupported)
+	case len(r.groupBy) > 0 || r.having.Len() > 0:
+		return fmt.Errorf("%w: GROUP BY or HAVING", ErrSplitUnsupported)
+	case r.distinct:
+		return fmt.Errorf("%w: DISTINCT", ErrSplitUnsupported)
+	case len(r.union) > 0:
+		return fmt.Errorf("%w: UNION", ErrSplitUnsupported)
+	}
+
+	for _, c := range r.columns {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(c.sql)), "DISTINCT ") {
+			return fmt.Errorf("%w: DISTINCT", ErrSplitUnsupported)
+		}
+		if aggregateCall.MatchString(c.sql) {
+			return fmt.Errorf("%w: aggregate %s", ErrSplitUnsupported, c.sql)
+		}
+	}
+	return nil
+}
+
+// uniqueValues returns in without repeated values keeping the order of their first occurrences
+func uniqueValues(in []interface{}) []interface{} {
+	seen := make(map[interface{}]struct{}, len(in))
+	unique := make([]interface{}, 0, len(in))
+	for _, v := range in {
+		key := v
+		if b, ok := v.([]byte); ok {
+			key = string(b)
+		} else if v != nil && !reflect.TypeOf(v).Comparable() {
+			unique = append(unique, v)
+			continue
+		}
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// tempColumnType picks the temporary table column type fitting the value
+func tempColumnType(v interface{}) string {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return TypeBigInt
+	case float32, float64:
+		return TypeDouble
+	case time.Time:
+		return TypeTimestamp
+	default:
+		return TypeVarchar + "(255)"
+	}
+}
+
+// argSize estimates the amount of bytes the bound value takes in the statement
+func argSize(v interface{}) int {
+	switch val := v.(type) {
+	case nil:
+		return 4
+	case string:
+		return len(val) + 2
+	case []byte:
+		return 2*len(val) + 3
+	case int64:
+		return len(strconv.FormatInt(val, 10))
+	case int:
+		return len(strconv.Itoa(val))
+	default:
+		return 20
+	}
+}
@@ -0,0 +1,95 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_SplitInsertBatch(t *testing.T) {
+	rows := make([]map[string]interface{}, 5)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": i, "name": "n"}
+	}
+
+	stmts, err := newDB(conn).Table("users").SplitInsertBatch(rows, SplitOptions{MaxParams: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []Statement{
+		{SQL: "INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?)", Args: []interface{}{0, "n", 1, "n"}, Kind: KindInsert, Table: "users", Dialect: DialectMySQL},
+		{SQL: "INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?)", Args: []interface{}{2, "n", 3, "n"}, Kind: KindInsert, Table: "users", Dialect: DialectMySQL},
+		{SQL: "INSERT INTO `users` (`id`, `name`) VALUES (?, ?)", Args: []interface{}{4, "n"}, Kind: KindInsert, Table: "users", Dialect: DialectMySQL},
+	}, stmts)
+
+	stmts, err = newDB(conn).Table("users").SplitInsertBatch(rows, SplitOptions{MaxBytes: 70})
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 3)
+	for _, stmt := range stmts {
+		assert.LessOrEqual(t, len(stmt.SQL), 70)
+	}
+
+	_, err = newDB(conn).Table("users").SplitInsertBatch(rows, SplitOptions{MaxParams: 1})
+	assert.ErrorIs(t, err, ErrTooManyColumns)
+	_, err = newDB(conn).Table("users").SplitInsertBatch(rows, SplitOptions{MaxBytes: 10})
+	assert.ErrorIs(t, err, ErrStatementTooLarge)
+}
+
+func TestDB_SplitWhereIn(t *testing.T) {
+	stmts, err := newDB(conn).Table("users").Where("active", OpEQ, 1).
+		SplitWhereIn("id", []interface{}{1, 2, 3, 4, 5}, SplitOptions{MaxParams: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []Statement{
+		{SQL: "SELECT * FROM `users` WHERE (`users`.`active` = ?) AND `users`.`id` IN (?, ?)", Args: []interface{}{1, 1, 2}, Kind: KindSelect, Table: "users", Dialect: DialectMySQL},
+		{SQL: "SELECT * FROM `users` WHERE (`users`.`active` = ?) AND `users`.`id` IN (?, ?)", Args: []interface{}{1, 3, 4}, Kind: KindSelect, Table: "users", Dialect: DialectMySQL},
+		{SQL: "SELECT * FROM `users` WHERE (`users`.`active` = ?) AND `users`.`id` IN (?)", Args: []interface{}{1, 5}, Kind: KindSelect, Table: "users", Dialect: DialectMySQL},
+	}, stmts)
+
+	stmts, err = newDB(conn).Table("users").Where("active", OpEQ, 1).
+		SplitWhereIn("id", []interface{}{1, 2, 1, 3, 2}, SplitOptions{MaxParams: 2, TempTable: "tmp_ids", TempTableAfter: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []Statement{
+		{SQL: "CREATE TEMPORARY TABLE `tmp_ids` (`v` BIGINT)", Kind: KindDDL, Table: "tmp_ids", Dialect: DialectMySQL},
+		{SQL: "INSERT INTO `tmp_ids` (`v`) VALUES (?), (?)", Args: []interface{}{1, 2}, Kind: KindInsert, Table: "tmp_ids", Dialect: DialectMySQL},
+		{SQL: "INSERT INTO `tmp_ids` (`v`) VALUES (?)", Args: []interface{}{3}, Kind: KindInsert, Table: "tmp_ids", Dialect: DialectMySQL},
+		{SQL: "SELECT * FROM `users` INNER JOIN `tmp_ids` ON `users`.`id` = `tmp_ids`.`v` WHERE `users`.`active` = ?", Args: []interface{}{1}, Kind: KindSelect, Table: "users", Dialect: DialectMySQL},
+		{SQL: "DROP TEMPORARY TABLE `tmp_ids`", Kind: KindDDL, Table: "tmp_ids", Dialect: DialectMySQL},
+	}, stmts)
+
+	_, err = newDB(conn).Table("users").SplitWhereIn("id", nil, SplitOptions{})
+	assert.ErrorIs(t, err, ErrEmptyWhereInValues)
+}
+
+func TestDB_SplitWhereInQualified(t *testing.T) {
+	stmts, err := newDB(conn).Table("users").SplitWhereIn("users.id", []interface{}{1, 2, 3}, SplitOptions{MaxParams: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE `users`.`id` IN (?, ?)", stmts[0].SQL)
+
+	stmts, err = newDB(conn).Table("users").
+		SplitWhereIn("users.id", []interface{}{1, 2, 3}, SplitOptions{TempTable: "tmp_ids", TempTableAfter: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` INNER JOIN `tmp_ids` ON `users`.`id` = `tmp_ids`.`v`", stmts[2].SQL)
+
+	_, err = newDB(conn).Table("users").AllowColumns("id").SplitWhereIn("password", []interface{}{1}, SplitOptions{})
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+	_, err = newDB(conn).Table("users").AllowColumns("id").
+		SplitWhereIn("password", []interface{}{1, 2}, SplitOptions{TempTable: "tmp_ids"})
+	assert.ErrorIs(t, err, ErrColumnNotAllowed)
+}
+
+func TestDB_SplitWhereInUnsupported(t *testing.T) {
+	in := []interface{}{1, 2, 3}
+	for name, q := range map[string]*DB{
+		"limit":     newDB(conn).Table("users").Limit(10),
+		"group by":  newDB(conn).Table("users").Select("status").GroupBy("status"),
+		"distinct":  newDB(conn).Table("users").Select("status").Distinct(),
+		"aggregate": newDB(conn).Table("users").SelectRaw("COUNT(*)"),
+		"union":     newDB(conn).Table("posts").Select("id").Union().Table("users").Select("id"),
+	} {
+		_, err := q.SplitWhereIn("id", in, SplitOptions{MaxParams: 2})
+		assert.ErrorIs(t, err, ErrSplitUnsupported, name)
+	}
+
+	// the temporary table keeps the query whole
+	stmts, err := newDB(conn).Table("users").Limit(10).SplitWhereIn("id", in, SplitOptions{TempTable: "tmp_ids"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` INNER JOIN `tmp_ids` ON `users`.`id` = `tmp_ids`.`v` LIMIT 10", stmts[2].SQL)
+}
@@ -1,6 +1,7 @@
 package buildsqlx
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -43,9 +44,55 @@ var ops = [...]string{
 	OpNotBetween: "NOT BETWEEN",
 }
 
+// Errors recorded by the clauses builders
+var (
+	ErrInvalidOp        = errors.New("sql: invalid operator")
+	ErrInvalidDirection = errors.New("sql: order direction must be ASC or DESC")
+	ErrColumnNotAllowed = errors.New("sql: column is not allowed")
+)
+
 type sqlBuilder struct {
-	sb   *strings.Builder
-	args []interface{}
+	sb      *strings.Builder
+	args    []interface{}
+	dialect string
+	err     error
+	// allowed columns, any column is allowed if nil
+	allowed map[string]struct{}
+}
+
+// derive returns an empty builder sharing the dialect, errors and allowed columns of b
+func (b *sqlBuilder) derive() *sqlBuilder {
+	return &sqlBuilder{dialect: b.dialect, err: b.err, allowed: b.allowed}
+}
+
+// clone returns a copy of b with its own buffer and arguments
+func (b *sqlBuilder) clone() *sqlBuilder {
+	c := b.derive()
+	c.args = append([]interface{}(nil), b.args...)
+	if b.sb != nil {
+		c.WriteString(b.sb.String())
+	}
+	return c
+}
+
+// allow records an error if the column isn't allowed
+func (b *sqlBuilder) allow(col string) bool {
+	if b.allowed == nil {
+		return true
+	}
+	if _, ok := b.allowed[col]; ok {
+		return true
+	}
+
+	b.setErr(fmt.Errorf("%w: %q", ErrColumnNotAllowed, col))
+	return false
+}
+
+// setErr records the error unless there is one already
+func (b *sqlBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
 }
 
 // Query returns query representation of a predicate.
@@ -92,7 +139,7 @@ func (b *sqlBuilder) WriteOp(op Op) *sqlBuilder {
 	case op == OpIsNull || op == OpNotNull:
 		b.Pad().WriteString(ops[op])
 	default:
-		panic(fmt.Sprintf("invalid op %d", op))
+		b.setErr(fmt.Errorf("%w: %d", ErrInvalidOp, op))
 	}
 	return b
 }
@@ -141,25 +188,46 @@ func (b *sqlBuilder) Ident(str string) *sqlBuilder {
 	return b
 }
 
+// idents adds a comma separated list of identifiers to the query.
+func (b *sqlBuilder) idents(idents []string) *sqlBuilder {
+	for i, ident := range idents {
+		if i > 0 {
+			b.Comma()
+		}
+		b.Ident(ident)
+	}
+	return b
+}
+
 // Ident adds a point to the query.
 func (b *sqlBuilder) IdentPoint(str string) *sqlBuilder {
 	b.WriteString(b.Quote(str)).WriteByte('.')
 	return b
 }
 
-// Ident adds a comma to the query.
+// Column adds the column qualified with the table unless it's qualified already,
+// the column is checked against allowed columns
+func (b *sqlBuilder) Column(table, col string) *sqlBuilder {
+	b.allow(col)
+	if !strings.Contains(col, ".") {
+		b.IdentPoint(table)
+	}
+	return b.Ident(col)
+}
+
+// Quote quotes the identifier according to the dialect.
 func (b *sqlBuilder) Quote(ident string) string {
-	quote := "`"
-	return quote + ident + quote
+	return quoteIdent(b.dialect, ident)
 }
 
 // Nested gets a callback, and wraps its result with parentheses.
 func (b *sqlBuilder) Nested(f func(*sqlBuilder)) *sqlBuilder {
-	nb := &sqlBuilder{sb: &strings.Builder{}}
+	nb := b.derive()
 	nb.WriteByte('(')
 	f(nb)
 	nb.WriteByte(')')
 	b.WriteString(nb.String())
 	b.args = append(b.args, nb.args...)
+	b.setErr(nb.err)
 	return b
 }
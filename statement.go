@@ -0,0 +1,230 @@
+package buildsqlx
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of sql statement
+type Kind string
+
+const (
+	KindSelect Kind = "select"
+	KindInsert Kind = "insert"
+	KindUpdate Kind = "update"
+	KindDelete Kind = "delete"
+	KindUpsert Kind = "upsert"
+	KindDDL    Kind = "ddl"
+	KindOther  Kind = "other"
+)
+
+// Statement is a single sql statement with its param bindings
+type Statement struct {
+	SQL  string
+	Args []interface{}
+	// Kind, Table and Dialect describe the statement for executors and logs
+	Kind    Kind
+	Table   string
+	Dialect string
+	// Err is the error of the builder, the statement must not be executed if it's set
+	Err error
+}
+
+// Execer runs generated statements not returning rows, it's implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// String returns the sql of the statement
+func (s Statement) String() string {
+	return s.SQL
+}
+
+// DebugSQL returns the sql with the bindings inlined as literals of the dialect, see ToDebugSQL
+func (s Statement) DebugSQL() (string, error) {
+	if s.Err != nil {
+		return "", s.Err
+	}
+
+	return interpolate(s.Dialect, s.SQL, s.Args)
+}
+
+// BoundSQL returns the sql with ? placeholders rewritten to the ones of the dialect driver:
+// $1, $2 ... on PostgreSQL, @p1, @p2 ... on SQL Server, MySQL and SQLite drivers take ? as is
+func (s Statement) BoundSQL() string {
+	var prefix string
+	switch s.Dialect {
+	case DialectPostgres:
+		prefix = "$"
+	case DialectSQLServer:
+		prefix = "@p"
+	default:
+		return s.SQL
+	}
+
+	query, _, _ := replacePlaceholders(s.Dialect, s.SQL, func(n int) (string, error) {
+		return prefix + strconv.Itoa(n+1), nil
+	})
+	return query
+}
+
+// Exec executes the statement with e rebinding its placeholders for the dialect, see BoundSQL.
+// The builder error is returned without executing anything
+func (s Statement) Exec(ctx context.Context, e Execer) (sql.Result, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	return e.ExecContext(ctx, s.BoundSQL(), s.Args...)
+}
+
+// Query runs the statement with q returning its rows rebinding its placeholders for the dialect, see BoundSQL.
+// The builder error is returned without running anything
+func (s Statement) Query(ctx context.Context, q Querier) (*sql.Rows, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	return q.QueryContext(ctx, s.BoundSQL(), s.Args...)
+}
+
+// statement describes query built by r
+func (r *DB) statement(kind Kind, query string, values []interface{}, err error) Statement {
+	if err == nil {
+		err = r.Err()
+	}
+
+	return Statement{
+		SQL:     query,
+		Args:    values,
+		Kind:    kind,
+		Table:   r.Builder.table,
+		Dialect: r.Builder.dialect,
+		Err:     err,
+	}
+}
+
+// Stmt wraps the result of a terminal method into Statement, the kind is detected by the leading keyword, ex.:
+//
+//	stmt := db.Stmt(db.Table("users").Avg("points"))
+func (r *DB) Stmt(query string, values []interface{}) Statement {
+	return r.statement(kindOf(query), query, values, nil)
+}
+
+// StmtE wraps the result of a terminal method returning an error into Statement, ex.:
+//
+//	stmt := db.StmtE(db.Table("users").Upsert(data, []string{"id"}, nil))
+func (r *DB) StmtE(query string, values []interface{}, err error) Statement {
+	return r.statement(kindOf(query), query, values, err)
+}
+
+// QueryStmt returns the select query as Statement
+func (r *DB) QueryStmt() Statement {
+	query, values := r.Query()
+	return r.statement(KindSelect, query, values, nil)
+}
+
+// InsertStmt returns Insert as Statement
+func (r *DB) InsertStmt(data map[string]interface{}) Statement {
+	query, values := r.Insert(data)
+	return r.statement(KindInsert, query, values, nil)
+}
+
+// InsertBatchStmt returns InsertBatch as Statement
+func (r *DB) InsertBatchStmt(data []map[string]interface{}, columns ...string) Statement {
+	query, values, err := r.InsertBatch(data, columns...)
+	return r.statement(KindInsert, query, values, err)
+}
+
+// UpdateStmt returns Update as Statement
+func (r *DB) UpdateStmt(data map[string]interface{}) Statement {
+	query, values := r.Update(data)
+	return r.statement(KindUpdate, query, values, nil)
+}
+
+// DeleteStmt returns Delete as Statement
+func (r *DB) DeleteStmt() Statement {
+	query, values := r.Delete()
+	return r.statement(KindDelete, query, values, nil)
+}
+
+// UpsertStmt returns Upsert as Statement
+func (r *DB) UpsertStmt(data map[string]interface{}, conflictCols, updateCols []string) Statement {
+	query, values, err := r.Upsert(data, conflictCols, updateCols)
+	return r.statement(KindUpsert, query, values, err)
+}
+
+// DropStmt returns Drop as Statement
+func (r *DB) DropStmt(tables string) Statement {
+	return r.ddlStatement(tables, r.Drop(tables))
+}
+
+// DropIfExistsStmt returns DropIfExists as Statement
+func (r *DB) DropIfExistsStmt(tables string) Statement {
+	return r.ddlStatement(tables, r.DropIfExists(tables))
+}
+
+// TruncateStmt returns Truncate as Statement
+func (r *DB) TruncateStmt(tables string) Statement {
+	return r.ddlStatement(tables, r.Truncate(tables))
+}
+
+// RenameStmt returns Rename as Statement
+func (r *DB) RenameStmt(from, to string) Statement {
+	return r.ddlStatement(from, r.Rename(from, to))
+}
+
+// CreateTableStmts returns CreateTable as statements, the error is set to each of them as well
+func (r *DB) CreateTableStmts(tblName string, fn func(table *Table) error) ([]Statement, error) {
+	sql, err := r.CreateTable(tblName, fn)
+	return r.ddlStatements(tblName, sql, err), err
+}
+
+// ModifyTableStmts returns ModifyTable as statements, the error is set to each of them as well
+func (r *DB) ModifyTableStmts(tblName string, fn func(table *Table) error) ([]Statement, error) {
+	sql, err := r.ModifyTable(tblName, fn)
+	return r.ddlStatements(tblName, sql, err), err
+}
+
+func (r *DB) ddlStatement(table, query string) Statement {
+	return Statement{SQL: query, Kind: KindDDL, Table: table, Dialect: r.Builder.dialect}
+}
+
+func (r *DB) ddlStatements(table string, sql []string, err error) []Statement {
+	stmts := make([]Statement, len(sql))
+	for i, query := range sql {
+		stmts[i] = r.ddlStatement(table, query)
+		stmts[i].Err = err
+	}
+	return stmts
+}
+
+// kindOf detects the kind of sql stmt by its leading keyword
+func kindOf(query string) Kind {
+	tokens := tokenize(query)
+	if len(tokens) == 0 {
+		return KindOther
+	}
+
+	switch strings.ToUpper(tokens[0].text) {
+	case "SELECT", "WITH":
+		return KindSelect
+	case "INSERT":
+		upper := strings.ToUpper(query)
+		if strings.Contains(upper, " ON CONFLICT ") || strings.Contains(upper, " ON DUPLICATE KEY UPDATE ") {
+			return KindUpsert
+		}
+		return KindInsert
+	case "REPLACE", "MERGE":
+		return KindUpsert
+	case "UPDATE":
+		return KindUpdate
+	case "DELETE":
+		return KindDelete
+	case "CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME":
+		return KindDDL
+	}
+	return KindOther
+}
@@ -0,0 +1,86 @@
+package buildsqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExecer struct {
+	query string
+	args  []interface{}
+}
+
+func (e *fakeExecer) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.query, e.args = query, args
+	return nil, nil
+}
+
+func TestDB_QueryStmt(t *testing.T) {
+	stmt := newDB(&Connection{driver: "postgres"}).Table("users").Where("id", OpEQ, 1).QueryStmt()
+	assert.Equal(t, Statement{
+		SQL:     `SELECT * FROM "users" WHERE "users"."id" = ?`,
+		Args:    []interface{}{1},
+		Kind:    KindSelect,
+		Table:   "users",
+		Dialect: DialectPostgres,
+	}, stmt)
+	assert.Equal(t, stmt.SQL, fmt.Sprint(stmt))
+
+	debug, err := stmt.DebugSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE "users"."id" = 1`, debug)
+
+	stmt = newDB(conn).Table("users").Where("id", Op(100), 1).QueryStmt()
+	assert.ErrorIs(t, stmt.Err, ErrInvalidOp)
+}
+
+func TestDB_Stmt(t *testing.T) {
+	db := newDB(conn)
+	assert.Equal(t, KindSelect, db.Stmt(db.Table("users").Avg("points")).Kind)
+	assert.Equal(t, KindUpsert, db.StmtE(db.Table("users").Upsert(map[string]interface{}{"id": 1}, []string{"id"}, nil)).Kind)
+	assert.Equal(t, KindInsert, db.InsertBatchStmt([]map[string]interface{}{{"id": 1}}).Kind)
+	assert.Equal(t, KindDelete, db.Table("users").DeleteStmt().Kind)
+
+	stmt := db.RenameStmt("users", "people")
+	assert.Equal(t, Statement{SQL: "ALTER TABLE `users` RENAME TO `people`", Kind: KindDDL, Table: "users", Dialect: DialectMySQL}, stmt)
+
+	stmts, err := db.CreateTableStmts("users", func(table *Table) error {
+		table.Increments("id")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 1)
+	assert.Equal(t, KindDDL, stmts[0].Kind)
+	assert.Equal(t, "users", stmts[0].Table)
+}
+
+func TestStatement_Exec(t *testing.T) {
+	e := &fakeExecer{}
+	_, err := newDB(conn).Table("users").Where("id", OpEQ, 1).UpdateStmt(map[string]interface{}{"name": "foo"}).Exec(context.Background(), e)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name` = ? WHERE `users`.`id` = ?", e.query)
+	assert.Equal(t, []interface{}{"foo", 1}, e.args)
+
+	e = &fakeExecer{}
+	_, err = newDB(&Connection{driver: "postgres"}).Table("users").Where("id", OpEQ, 1).Where("note", OpNEQ, "?").
+		UpdateStmt(map[string]interface{}{"name": "foo"}).Exec(context.Background(), e)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name" = $1 WHERE "users"."id" = $2 AND "users"."note" <> $3`, e.query)
+
+	e = &fakeExecer{}
+	_, err = newDB(&Connection{driver: "sqlserver"}).Table("users").Where("id", OpEQ, 1).DeleteStmt().Exec(context.Background(), e)
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM [users] WHERE [users].[id] = @p1`, e.query)
+
+	stmt := Statement{SQL: `SELECT '?' AS "a?" FROM "t" WHERE "id" = ?`, Dialect: DialectPostgres}
+	assert.Equal(t, `SELECT '?' AS "a?" FROM "t" WHERE "id" = $1`, stmt.BoundSQL())
+
+	e = &fakeExecer{}
+	_, err = newDB(conn).InsertStmt(map[string]interface{}{"name": "foo"}).Exec(context.Background(), e)
+	assert.ErrorIs(t, err, ErrNoTable)
+	assert.Empty(t, e.query)
+}
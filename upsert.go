@@ -0,0 +1,186 @@
+package buildsqlx
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNoConflictColumns      = errors.New("sql: conflict columns are required by the dialect")
+	ErrUpsertWhereUnsupported = errors.New("sql: conditional upsert is not supported by the dialect")
+)
+
+// RowAlias sets the alias of inserted row referred by ON DUPLICATE KEY UPDATE on MySQL 8.0.19+,
+// instead of the deprecated VALUES(col) function
+func (r *DB) RowAlias(alias string) *DB {
+	r.Builder.rowAlias = alias
+	return r
+}
+
+// Upsert inserts the row or updates updateCols of the row conflicting on conflictCols, see UpsertBatch
+func (r *DB) Upsert(data map[string]interface{}, conflictCols, updateCols []string) (query string, values []interface{}, err error) {
+	return r.UpsertBatch([]map[string]interface{}{data}, conflictCols, updateCols)
+}
+
+// UpsertBatch inserts the rows or updates updateCols of the rows conflicting on conflictCols, it renders:
+//
+//	MySQL:              INSERT ... ON DUPLICATE KEY UPDATE col = VALUES(col)
+//	PostgreSQL, SQLite: INSERT ... ON CONFLICT (conflictCols) DO UPDATE SET col = EXCLUDED.col
+//	SQL Server:         MERGE INTO ... USING (VALUES ...) ON ... WHEN MATCHED THEN UPDATE ... WHEN NOT MATCHED THEN INSERT ...
+//
+// Conflicting rows are left intact if updateCols is empty. Where conditions of the query limit
+// the rows to be updated on PostgreSQL, SQLite and SQL Server
+func (r *DB) UpsertBatch(data []map[string]interface{}, conflictCols, updateCols []string) (query string, values []interface{}, err error) {
+	builder := r.Builder
+	if builder.table == "" {
+		return "", nil, ErrNoTable
+	}
+
+	columns, err := prepareInsertBatch(data, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch {
+	case isMySQL(builder.dialect):
+		if builder.where.Len() > 0 {
+			return "", nil, ErrUpsertWhereUnsupported
+		}
+		values = builder.writeOnDuplicateKey(columns, data, conflictCols, updateCols)
+		builder.composeReturning("INSERT")
+	case builder.dialect == DialectSQLServer:
+		if len(conflictCols) == 0 {
+			return "", nil, ErrNoConflictColumns
+		}
+		values = builder.writeMerge(columns, data, conflictCols, updateCols)
+	default:
+		if len(conflictCols) == 0 && len(updateCols) > 0 {
+			return "", nil, ErrNoConflictColumns
+		}
+		values = builder.writeOnConflict(columns, data, conflictCols, updateCols)
+		builder.composeReturning("INSERT")
+	}
+
+	return r.built(values)
+}
+
+// writeOnDuplicateKey writes MySQL INSERT ... ON DUPLICATE KEY UPDATE statement
+func (r *builder) writeOnDuplicateKey(columns []string, data []map[string]interface{}, conflictCols, updateCols []string) []interface{} {
+	values := r.writeInsertBatch(insertInto, columns, data)
+	if r.rowAlias != "" {
+		r.Pad().WriteString("AS").Pad().Ident(r.rowAlias)
+	}
+
+	r.Pad().WriteString("ON DUPLICATE KEY UPDATE").Pad()
+	if len(updateCols) == 0 {
+		// no-op assignment keeps the conflicting row intact without swallowing other errors like INSERT IGNORE
+		col := columns[0]
+		if len(conflictCols) > 0 {
+			col = conflictCols[0]
+		}
+		r.Ident(col).WriteOp(OpEQ).Ident(col)
+		return values
+	}
+
+	for i, col := range updateCols {
+		if i > 0 {
+			r.Comma()
+		}
+
+		r.Ident(col).WriteOp(OpEQ)
+		if r.rowAlias != "" {
+			r.IdentPoint(r.rowAlias).Ident(col)
+		} else {
+			r.WriteString("VALUES").WriteByte('(').Ident(col).WriteByte(')')
+		}
+	}
+
+	return values
+}
+
+// writeOnConflict writes PostgreSQL/SQLite INSERT ... ON CONFLICT statement
+func (r *builder) writeOnConflict(columns []string, data []map[string]interface{}, conflictCols, updateCols []string) []interface{} {
+	values := r.writeInsertBatch(insertInto, columns, data)
+
+	r.Pad().WriteString("ON CONFLICT")
+	if len(conflictCols) > 0 {
+		r.Pad().Nested(func(s *sqlBuilder) {
+			s.idents(conflictCols)
+		})
+	}
+
+	if len(updateCols) == 0 {
+		r.Pad().WriteString("DO NOTHING")
+		return values
+	}
+
+	r.Pad().WriteString("DO UPDATE SET").Pad()
+	for i, col := range updateCols {
+		if i > 0 {
+			r.Comma()
+		}
+		r.Ident(col).WriteOp(OpEQ).WriteString("EXCLUDED.").Ident(col)
+	}
+
+	if r.where.Len() > 0 {
+		r.WriteString(r.where.String())
+		values = append(values, r.where.args...)
+	}
+
+	return values
+}
+
+// writeMerge writes SQL Server MERGE statement
+func (r *builder) writeMerge(columns []string, data []map[string]interface{}, conflictCols, updateCols []string) []interface{} {
+	const source = "source"
+
+	r.WriteString("MERGE INTO").Pad().Ident(r.table).Pad().WriteString("WITH (HOLDLOCK)").Pad().
+		WriteString("USING").Pad().WriteByte('(').WriteString("VALUES").Pad()
+	// table value constructor has no DEFAULT
+	values := r.writeRows(columns, data, "NULL")
+	r.WriteByte(')').Pad().WriteString("AS").Pad().Ident(source).Pad().Nested(func(s *sqlBuilder) {
+		s.idents(columns)
+	})
+
+	r.Pad().WriteString("ON").Pad()
+	for i, col := range conflictCols {
+		if i > 0 {
+			r.WriteString(and)
+		}
+		r.IdentPoint(r.table).Ident(col).WriteOp(OpEQ).IdentPoint(source).Ident(col)
+	}
+
+	if len(updateCols) > 0 {
+		r.Pad().WriteString("WHEN MATCHED")
+		if r.where.Len() > 0 {
+			r.WriteString(and).WriteString(strings.TrimPrefix(r.where.String(), where))
+			values = append(values, r.where.args...)
+		}
+
+		r.Pad().WriteString("THEN UPDATE SET").Pad()
+		for i, col := range updateCols {
+			if i > 0 {
+				r.Comma()
+			}
+			r.Ident(col).WriteOp(OpEQ).IdentPoint(source).Ident(col)
+		}
+	}
+
+	r.Pad().WriteString("WHEN NOT MATCHED THEN INSERT").Pad().
+		Nested(func(s *sqlBuilder) {
+			s.idents(columns)
+		}).
+		Pad().WriteString("VALUES").Pad().
+		Nested(func(s *sqlBuilder) {
+			for i, col := range columns {
+				if i > 0 {
+					s.Comma()
+				}
+				s.IdentPoint(source).Ident(col)
+			}
+		})
+	r.composeOutput(outputInserted)
+	r.WriteByte(';')
+
+	return values
+}
@@ -0,0 +1,59 @@
+package buildsqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_Upsert(t *testing.T) {
+	data := map[string]interface{}{"email": "a@b.c", "name": "John", "points": 10}
+	conflict, update := []string{"email"}, []string{"name", "points"}
+
+	query, values, err := newDB(&Connection{driver: "mysql"}).Table("users").Upsert(data, conflict, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`email`, `name`, `points`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `points` = VALUES(`points`)", query)
+	assert.Equal(t, []interface{}{"a@b.c", "John", 10}, values)
+
+	query, _, err = newDB(&Connection{driver: "mysql"}).Table("users").RowAlias("new").Upsert(data, conflict, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`email`, `name`, `points`) VALUES (?, ?, ?) AS `new` ON DUPLICATE KEY UPDATE `name` = `new`.`name`, `points` = `new`.`points`", query)
+
+	query, _, err = newDB(&Connection{driver: "mysql"}).Table("users").Upsert(data, conflict, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`email`, `name`, `points`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `email` = `email`", query)
+
+	_, _, err = newDB(&Connection{driver: "mysql"}).Table("users").Where("points", OpLT, 5).Upsert(data, conflict, update)
+	assert.ErrorIs(t, err, ErrUpsertWhereUnsupported)
+
+	query, values, err = newDB(&Connection{driver: "postgres"}).Table("users").Where("points", OpLT, 5).Upsert(data, conflict, update)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("email", "name", "points") VALUES (?, ?, ?) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "points" = EXCLUDED."points" WHERE "users"."points" < ?`, query)
+	assert.Equal(t, []interface{}{"a@b.c", "John", 10, 5}, values)
+
+	query, _, err = newDB(&Connection{driver: "sqlite3"}).Table("users").Upsert(data, conflict, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("email", "name", "points") VALUES (?, ?, ?) ON CONFLICT ("email") DO NOTHING`, query)
+
+	_, _, err = newDB(&Connection{driver: "postgres"}).Table("users").Upsert(data, nil, update)
+	assert.ErrorIs(t, err, ErrNoConflictColumns)
+}
+
+func TestDB_UpsertBatchMerge(t *testing.T) {
+	query, values, err := newDB(&Connection{driver: "sqlserver"}).Table("users").Where("points", OpLT, 5).UpsertBatch([]map[string]interface{}{
+		{"email": "a@b.c", "name": "John"},
+		{"email": "d@e.f"},
+	}, []string{"email"}, []string{"name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO [users] WITH (HOLDLOCK) USING (VALUES (?, ?), (?, NULL)) AS [source] ([email], [name])"+
+		" ON [users].[email] = [source].[email]"+
+		" WHEN MATCHED AND [users].[points] < ? THEN UPDATE SET [name] = [source].[name]"+
+		" WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES ([source].[email], [source].[name]);", query)
+	assert.Equal(t, []interface{}{"a@b.c", "John", "d@e.f", 5}, values)
+}
+
+func TestDB_Replace(t *testing.T) {
+	query, values := newDB(conn).Table("users").Replace(map[string]interface{}{"id": 1, "name": "John"}, "id")
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", query)
+	assert.Equal(t, []interface{}{1, "John"}, values)
+}